@@ -1,18 +1,111 @@
 package analytics
 
+import (
+	"fmt"
+
+	"grind/types"
+)
+
 type TokenAnalyzerConfig struct {
-	MinLiquidity   float64
-	MinHolderCount int
-	MaxTopHolder   float64
-	MinAge         int64
+	MinLiquidity   float64 `json:"minLiquidity"`
+	MaxMarketCap   float64 `json:"maxMarketCap"`
+	MinHolderCount int     `json:"minHolders"`
+	MaxTopHolder   float64 `json:"maxTopHolder"`
+	MinAge         int64   `json:"minAge"`      // Seconds since the pair was created
+	MinLockTime    int64   `json:"minLockTime"` // Seconds of liquidity lock remaining
+	// Rules replaces the rules derived from the fields above when set
+	Rules []Rule `json:"rules"`
 }
 
 type TokenAnalyzer struct {
 	config TokenAnalyzerConfig
+	rules  []Rule
 }
 
-func NewTokenAnalyzer(config TokenAnalyzerConfig) *TokenAnalyzer {
+func NewTokenAnalyzer(config TokenAnalyzerConfig) (*TokenAnalyzer, error) {
+	rules := config.Rules
+	if len(rules) == 0 {
+		rules = DefaultRules(config)
+	}
+
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &TokenAnalyzer{
 		config: config,
+		rules:  rules,
+	}, nil
+}
+
+// DefaultRules is the built-in rule set with the config's thresholds. A zero
+// threshold is taken as given; config.Default holds the usual values.
+func DefaultRules(config TokenAnalyzerConfig) []Rule {
+	minLockDays := float64(config.MinLockTime) / (24 * 60 * 60)
+
+	rules := []Rule{
+		{Name: "liquidity", Field: FieldLiquidity, Operator: OpGreaterOrEqual, Threshold: config.MinLiquidity, Severity: SeverityReject},
+		{Name: "market_cap", Field: FieldMarketCap, Operator: OpLessOrEqual, Threshold: config.MaxMarketCap, Severity: SeverityReject},
+		{Name: "liquidity_lock", Field: FieldLiquidityLocked, Operator: OpEqual, Threshold: 1, Severity: SeverityReject},
+		{Name: "lock_duration", Field: FieldLockDurationDays, Operator: OpGreaterOrEqual, Threshold: minLockDays, Severity: SeverityReject},
+		{Name: "honeypot", Field: FieldIsHoneypot, Operator: OpEqual, Threshold: 0, Severity: SeverityReject},
+		{Name: "top_holder_share", Field: FieldTopHolderShare, Operator: OpLessOrEqual, Threshold: config.MaxTopHolder, Severity: SeverityReject},
+		{Name: "holder_count", Field: FieldHolderCount, Operator: OpGreaterOrEqual, Threshold: float64(config.MinHolderCount), Severity: SeverityReject},
+		{Name: "social_presence", Field: FieldSocialScore, Operator: OpGreaterOrEqual, Threshold: 2, Severity: SeverityReject},
+		{Name: "metadata_mutability", Field: FieldMetadataMutable, Operator: OpEqual, Threshold: 0, Severity: SeverityWarn},
+	}
+
+	if config.MinAge > 0 {
+		rules = append(rules, Rule{Name: "market_age", Field: FieldAgeSeconds, Operator: OpGreaterOrEqual, Threshold: float64(config.MinAge), Severity: SeverityReject})
 	}
+
+	return rules
+}
+
+func (a *TokenAnalyzer) Rules() []Rule {
+	return a.rules
+}
+
+// Evaluate checks every rule whose field is present in facts and reports each outcome.
+func (a *TokenAnalyzer) Evaluate(mint, symbol string, facts *Facts) *types.SafetyReport {
+	report := types.NewSafetyReport(mint, symbol)
+
+	for _, rule := range a.rules {
+		if unknown, ok := facts.unknown[rule.Field]; ok {
+			report.Add(types.SafetyCheck{
+				Name:      rule.Name,
+				Status:    types.CheckUnknown,
+				Threshold: rule.String(),
+				Source:    unknown.source,
+				Message:   fmt.Sprintf("%s unavailable: %s", rule.Name, unknown.reason),
+				Policy:    unknown.policy,
+			})
+			continue
+		}
+
+		fact, ok := facts.known[rule.Field]
+		if !ok {
+			continue
+		}
+
+		check := types.SafetyCheck{
+			Name:      rule.Name,
+			Status:    types.CheckPass,
+			Value:     formatValue(fact.value),
+			Threshold: rule.String(),
+			Source:    fact.source,
+		}
+		if !rule.Holds(fact.value) {
+			check.Status = types.CheckFail
+			if rule.Severity == SeverityWarn {
+				check.Status = types.CheckWarn
+			}
+			check.Message = fmt.Sprintf("%s: %s is %s, want %s", rule.Name, rule.Field, check.Value, rule.String())
+		}
+		report.Add(check)
+	}
+
+	return report
 }
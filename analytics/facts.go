@@ -0,0 +1,52 @@
+package analytics
+
+import "grind/types"
+
+type fact struct {
+	value  float64
+	source string
+}
+
+type unknownFact struct {
+	reason string
+	source string
+	policy types.CheckPolicy
+}
+
+// Facts are the measured values a token is judged on. Fields that were never
+// measured are absent and their rules are skipped; fields whose data source
+// failed are unknown and reported according to their failure policy.
+type Facts struct {
+	known   map[string]fact
+	unknown map[string]unknownFact
+}
+
+func NewFacts() *Facts {
+	return &Facts{
+		known:   make(map[string]fact),
+		unknown: make(map[string]unknownFact),
+	}
+}
+
+func (f *Facts) Set(field string, value float64, source string) {
+	f.known[field] = fact{value: value, source: source}
+	delete(f.unknown, field)
+}
+
+func (f *Facts) SetBool(field string, value bool, source string) {
+	if value {
+		f.Set(field, 1, source)
+	} else {
+		f.Set(field, 0, source)
+	}
+}
+
+func (f *Facts) SetUnknown(field, reason, source string, policy types.CheckPolicy) {
+	f.unknown[field] = unknownFact{reason: reason, source: source, policy: policy}
+	delete(f.known, field)
+}
+
+func (f *Facts) Value(field string) (float64, bool) {
+	fact, ok := f.known[field]
+	return fact.value, ok
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type Operator string
+
+const (
+	OpGreaterThan    Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	OpLessThan       Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpEqual          Operator = "=="
+	OpNotEqual       Operator = "!="
+)
+
+type Severity string
+
+const (
+	SeverityReject Severity = "reject" // A violation fails the token
+	SeverityWarn   Severity = "warn"   // A violation is reported but does not fail the token
+)
+
+// Rule states a requirement a token must meet, e.g. liquidity >= 10000.
+type Rule struct {
+	Name      string   `json:"name"`
+	Field     string   `json:"field"`
+	Operator  Operator `json:"operator"`
+	Threshold float64  `json:"threshold"`
+	Severity  Severity `json:"severity"`
+}
+
+// Fields rules can refer to. Booleans are 1 for true and 0 for false.
+const (
+	FieldLiquidity        = "liquidity"
+	FieldVolume24h        = "volume_24h"
+	FieldMarketCap        = "market_cap"
+	FieldAgeSeconds       = "age_seconds"
+	FieldLiquidityLocked  = "liquidity_locked"
+	FieldLockDurationDays = "lock_duration_days"
+	FieldIsHoneypot       = "is_honeypot"
+	FieldTopHolderShare   = "top_holder_share"
+	FieldHolderCount      = "holder_count"
+	FieldSocialScore      = "social_score"
+	FieldMetadataMutable  = "metadata_mutable"
+	FieldHasWebsite       = "has_website"
+	FieldHasGitHub        = "has_github"
+	FieldHasWhitepaper    = "has_whitepaper"
+)
+
+var knownFields = map[string]bool{
+	FieldLiquidity:        true,
+	FieldVolume24h:        true,
+	FieldMarketCap:        true,
+	FieldAgeSeconds:       true,
+	FieldLiquidityLocked:  true,
+	FieldLockDurationDays: true,
+	FieldIsHoneypot:       true,
+	FieldTopHolderShare:   true,
+	FieldHolderCount:      true,
+	FieldSocialScore:      true,
+	FieldMetadataMutable:  true,
+	FieldHasWebsite:       true,
+	FieldHasGitHub:        true,
+	FieldHasWhitepaper:    true,
+}
+
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule has no name")
+	}
+	if !knownFields[r.Field] {
+		return fmt.Errorf("rule %s: unknown field %q", r.Name, r.Field)
+	}
+	switch r.Operator {
+	case OpGreaterThan, OpGreaterOrEqual, OpLessThan, OpLessOrEqual, OpEqual, OpNotEqual:
+	default:
+		return fmt.Errorf("rule %s: unknown operator %q", r.Name, r.Operator)
+	}
+	switch r.Severity {
+	case SeverityReject, SeverityWarn:
+	default:
+		return fmt.Errorf("rule %s: unknown severity %q", r.Name, r.Severity)
+	}
+	return nil
+}
+
+// Holds reports whether value satisfies the rule.
+func (r Rule) Holds(value float64) bool {
+	switch r.Operator {
+	case OpGreaterThan:
+		return value > r.Threshold
+	case OpGreaterOrEqual:
+		return value >= r.Threshold
+	case OpLessThan:
+		return value < r.Threshold
+	case OpLessOrEqual:
+		return value <= r.Threshold
+	case OpEqual:
+		return value == r.Threshold
+	case OpNotEqual:
+		return value != r.Threshold
+	}
+	return false
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s %s %s", r.Field, r.Operator, formatValue(r.Threshold))
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
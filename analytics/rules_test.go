@@ -0,0 +1,189 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+
+	"grind/types"
+)
+
+const sampleMint = "7GCihgDB8fe6KNjn2MYtkzZcRjQy3t9GHdC8uHYmW2hr"
+
+// testThresholds mirror the defaults config.Default gives the analyzer.
+var testThresholds = TokenAnalyzerConfig{
+	MinLiquidity:   10000,
+	MaxMarketCap:   1000000,
+	MinHolderCount: 100,
+	MaxTopHolder:   0.15,
+	MinLockTime:    30 * 24 * 60 * 60,
+}
+
+func TestRuleHolds(t *testing.T) {
+	for _, test := range []struct {
+		operator         Operator
+		below, at, above bool
+	}{
+		{OpGreaterThan, false, false, true},
+		{OpGreaterOrEqual, false, true, true},
+		{OpLessThan, true, false, false},
+		{OpLessOrEqual, true, true, false},
+		{OpEqual, false, true, false},
+		{OpNotEqual, true, false, true},
+		{"~=", false, false, false},
+	} {
+		rule := Rule{Name: "r", Field: FieldLiquidity, Operator: test.operator, Threshold: 10}
+		for _, value := range []struct {
+			value float64
+			want  bool
+		}{
+			{9.5, test.below},
+			{10, test.at},
+			{10.5, test.above},
+		} {
+			if got := rule.Holds(value.value); got != value.want {
+				t.Errorf("%v %s: Holds(%g) = %v, want %v", rule.Operator, formatValue(rule.Threshold), value.value, got, value.want)
+			}
+		}
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	valid := Rule{Name: "liquidity", Field: FieldLiquidity, Operator: OpGreaterOrEqual, Threshold: 1, Severity: SeverityReject}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("rejected a valid rule: %v", err)
+	}
+
+	for _, test := range []struct {
+		name  string
+		edit  func(r *Rule)
+		error string
+	}{
+		{"no name", func(r *Rule) { r.Name = "" }, "no name"},
+		{"unknown field", func(r *Rule) { r.Field = "liquidty" }, `unknown field "liquidty"`},
+		{"unknown operator", func(r *Rule) { r.Operator = "=>" }, `unknown operator "=>"`},
+		{"no operator", func(r *Rule) { r.Operator = "" }, "unknown operator"},
+		{"unknown severity", func(r *Rule) { r.Severity = "fatal" }, `unknown severity "fatal"`},
+	} {
+		rule := valid
+		test.edit(&rule)
+		err := rule.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("%s: got %v, want an error with %q", test.name, err, test.error)
+		}
+	}
+}
+
+func TestNewTokenAnalyzerRejectsInvalidRules(t *testing.T) {
+	_, err := NewTokenAnalyzer(TokenAnalyzerConfig{Rules: []Rule{
+		{Name: "ok", Field: FieldLiquidity, Operator: OpGreaterThan, Severity: SeverityReject},
+		{Name: "typo", Field: "volume", Operator: OpGreaterThan, Severity: SeverityReject},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "typo") {
+		t.Errorf("got %v, want the invalid rule named", err)
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	thresholds := func(rules []Rule) map[string]float64 {
+		byName := make(map[string]float64)
+		for _, rule := range rules {
+			byName[rule.Name] = rule.Threshold
+		}
+		return byName
+	}
+
+	defaults := thresholds(DefaultRules(testThresholds))
+	if defaults["liquidity"] != 10000 || defaults["holder_count"] != 100 || defaults["lock_duration"] != 30 {
+		t.Errorf("thresholds = %v", defaults)
+	}
+	if _, ok := defaults["market_age"]; ok {
+		t.Errorf("market_age rule without a minimum age")
+	}
+
+	// Explicit zeros are kept rather than replaced by the usual values
+	zeros := thresholds(DefaultRules(TokenAnalyzerConfig{MaxMarketCap: 1000000, MaxTopHolder: 0.15}))
+	if zeros["liquidity"] != 0 || zeros["holder_count"] != 0 || zeros["lock_duration"] != 0 {
+		t.Errorf("zero thresholds = %v", zeros)
+	}
+
+	configured := thresholds(DefaultRules(TokenAnalyzerConfig{MinLiquidity: 500, MinLockTime: 7 * 24 * 60 * 60, MinAge: 600}))
+	if configured["liquidity"] != 500 || configured["lock_duration"] != 7 || configured["market_age"] != 600 {
+		t.Errorf("configured thresholds = %v", configured)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	analyzer, err := NewTokenAnalyzer(testThresholds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	passing := func() *Facts {
+		facts := NewFacts()
+		facts.Set(FieldLiquidity, 25000, "raydium")
+		facts.Set(FieldMarketCap, 400000, "raydium")
+		facts.SetBool(FieldLiquidityLocked, true, "goplus")
+		facts.Set(FieldLockDurationDays, 90, "goplus")
+		facts.SetBool(FieldIsHoneypot, false, "goplus")
+		facts.Set(FieldTopHolderShare, 0.05, "solscan")
+		facts.Set(FieldHolderCount, 400, "solscan")
+		facts.Set(FieldSocialScore, 3, "social")
+		facts.SetBool(FieldMetadataMutable, false, "metadata")
+		return facts
+	}
+
+	for _, test := range []struct {
+		name       string
+		edit       func(f *Facts)
+		passed     bool
+		check      string
+		wantStatus types.CheckStatus
+	}{
+		{"all pass", func(f *Facts) {}, true, "liquidity", types.CheckPass},
+		{"failed rule", func(f *Facts) { f.Set(FieldLiquidity, 900, "raydium") }, false, "liquidity", types.CheckFail},
+		{"warn rule", func(f *Facts) { f.SetBool(FieldMetadataMutable, true, "metadata") }, true, "metadata_mutability", types.CheckWarn},
+		{"fail closed", func(f *Facts) {
+			f.SetUnknown(FieldHolderCount, "solscan timed out", "solscan", types.FailClosed)
+		}, false, "holder_count", types.CheckUnknown},
+		{"fail open", func(f *Facts) {
+			f.SetUnknown(FieldSocialScore, "rate limited", "social", types.FailOpen)
+		}, true, "social_presence", types.CheckUnknown},
+		{"no policy fails closed", func(f *Facts) {
+			f.SetUnknown(FieldIsHoneypot, "goplus down", "goplus", "")
+		}, false, "honeypot", types.CheckUnknown},
+	} {
+		facts := passing()
+		test.edit(facts)
+		report := analyzer.Evaluate(sampleMint, "POPCAT", facts)
+
+		if report.Passed() != test.passed {
+			t.Errorf("%s: passed = %v, want %v (%s)", test.name, report.Passed(), test.passed, report.Summary())
+		}
+		var found bool
+		for _, check := range report.Checks {
+			if check.Name == test.check {
+				found = true
+				if check.Status != test.wantStatus {
+					t.Errorf("%s: %s is %s, want %s", test.name, check.Name, check.Status, test.wantStatus)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: no %s check in %+v", test.name, test.check, report.Checks)
+		}
+	}
+}
+
+func TestEvaluateSkipsUnmeasuredFields(t *testing.T) {
+	analyzer, err := NewTokenAnalyzer(testThresholds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	facts := NewFacts()
+	facts.Set(FieldLiquidity, 25000, "raydium")
+
+	report := analyzer.Evaluate(sampleMint, "POPCAT", facts)
+	if len(report.Checks) != 1 || !report.Passed() {
+		t.Errorf("got %+v, want only the liquidity check", report.Checks)
+	}
+}
@@ -0,0 +1,234 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+
+	"grind/types"
+)
+
+// Scorer ranks tokens that passed the rules. Several can run side by side on
+// the same candidates so that models can be compared.
+type Scorer interface {
+	Name() string
+	Score(facts *Facts) types.ScoreBreakdown
+}
+
+const (
+	ModelHeuristic = "heuristic"
+	ModelLinear    = "linear"
+)
+
+// LinearTerm adds Weight * min(value, Cap) for Field; a zero Cap means uncapped.
+type LinearTerm struct {
+	Field  string  `json:"field"`
+	Weight float64 `json:"weight"`
+	Cap    float64 `json:"cap"`
+}
+
+type ScoringModel struct {
+	Name  string       `json:"name"`
+	Type  string       `json:"type"`
+	Bias  float64      `json:"bias"`
+	Terms []LinearTerm `json:"terms"`
+}
+
+type ScoringConfig struct {
+	// Primary names the model whose score drives decisions; defaults to the first
+	Primary string         `json:"primary"`
+	Models  []ScoringModel `json:"models"`
+}
+
+// NewScorers builds every configured model, primary first. With no models
+// configured it returns the heuristic alone. The heuristic scales its
+// components by the analyzer's thresholds.
+func NewScorers(config ScoringConfig, thresholds TokenAnalyzerConfig) ([]Scorer, error) {
+	if len(config.Models) == 0 {
+		return []Scorer{NewHeuristicScorer(ModelHeuristic, thresholds)}, nil
+	}
+
+	scorers := make([]Scorer, 0, len(config.Models))
+	seen := make(map[string]bool)
+	for _, model := range config.Models {
+		if model.Name == "" {
+			return nil, fmt.Errorf("scoring model has no name")
+		}
+		if seen[model.Name] {
+			return nil, fmt.Errorf("duplicate scoring model %s", model.Name)
+		}
+		seen[model.Name] = true
+
+		var scorer Scorer
+		switch model.Type {
+		case ModelHeuristic:
+			scorer = NewHeuristicScorer(model.Name, thresholds)
+		case ModelLinear:
+			for _, term := range model.Terms {
+				if !knownFields[term.Field] {
+					return nil, fmt.Errorf("scoring model %s: unknown field %q", model.Name, term.Field)
+				}
+			}
+			scorer = LinearScorer{name: model.Name, bias: model.Bias, terms: model.Terms}
+		default:
+			return nil, fmt.Errorf("scoring model %s: unknown type %q", model.Name, model.Type)
+		}
+
+		if model.Name == config.Primary {
+			scorers = append([]Scorer{scorer}, scorers...)
+		} else {
+			scorers = append(scorers, scorer)
+		}
+	}
+
+	if config.Primary != "" && !seen[config.Primary] {
+		return nil, fmt.Errorf("primary scoring model %s is not defined", config.Primary)
+	}
+
+	return scorers, nil
+}
+
+// HeuristicScorer is the original hand-tuned score: liquidity, trading
+// activity and room to grow, scaled by holder, lock and social multipliers.
+// A zero minimum is met by any positive value; a zero maximum market cap
+// gives no credit for room to grow.
+type HeuristicScorer struct {
+	name       string
+	thresholds TokenAnalyzerConfig
+}
+
+func NewHeuristicScorer(name string, thresholds TokenAnalyzerConfig) HeuristicScorer {
+	return HeuristicScorer{name: name, thresholds: thresholds}
+}
+
+func (s HeuristicScorer) Name() string {
+	if s.name == "" {
+		return ModelHeuristic
+	}
+	return s.name
+}
+
+func (s HeuristicScorer) Score(facts *Facts) types.ScoreBreakdown {
+	breakdown := types.ScoreBreakdown{Model: s.Name()}
+	thresholds := s.thresholds
+	liquidity, _ := facts.Value(FieldLiquidity)
+	volume, _ := facts.Value(FieldVolume24h)
+	marketCap, _ := facts.Value(FieldMarketCap)
+
+	// Liquidity weight (higher is better, up to five times the minimum)
+	addComponent(&breakdown, FieldLiquidity, ratio(liquidity, thresholds.MinLiquidity, 5.0), 20)
+
+	// Volume/Liquidity ratio (higher is better, indicates trading activity)
+	volumeRatio := 0.0
+	if liquidity > 0 {
+		volumeRatio = volume / liquidity
+	}
+	addComponent(&breakdown, "volume_ratio", math.Min(volumeRatio, 2.0), 50)
+
+	// Market cap (lower is better, more room to grow); never negative
+	room := 0.0
+	if thresholds.MaxMarketCap > 0 {
+		room = math.Max(0, 1.0-marketCap/thresholds.MaxMarketCap)
+	}
+	addComponent(&breakdown, FieldMarketCap, room, 30)
+
+	// Holder count bonus
+	holders, _ := facts.Value(FieldHolderCount)
+	addMultiplier(&breakdown, FieldHolderCount, holders, ratio(holders, float64(thresholds.MinHolderCount), 2.0))
+
+	// Top holder penalty (lower is better)
+	topHolder, _ := facts.Value(FieldTopHolderShare)
+	topHolderFactor := 1.0
+	if topHolder > 0.5 {
+		topHolderFactor = 0.5
+	}
+	addMultiplier(&breakdown, FieldTopHolderShare, topHolder, topHolderFactor)
+
+	// Liquidity lock bonus
+	locked, _ := facts.Value(FieldLiquidityLocked)
+	lockFactor := 1.0
+	if locked == 1 {
+		lockFactor = 1.2
+	}
+	addMultiplier(&breakdown, FieldLiquidityLocked, locked, lockFactor)
+
+	// Social presence bonus
+	social := 0.0
+	for _, field := range []string{FieldHasWebsite, FieldHasGitHub, FieldHasWhitepaper} {
+		if value, _ := facts.Value(field); value == 1 {
+			social += 0.1
+		}
+	}
+	addMultiplier(&breakdown, "social_bonus", social, 1.0+social)
+
+	breakdown.Total = total(breakdown)
+	return breakdown
+}
+
+// LinearScorer is a weighted sum of fact values with weights from config.
+type LinearScorer struct {
+	name  string
+	bias  float64
+	terms []LinearTerm
+}
+
+func (s LinearScorer) Name() string {
+	return s.name
+}
+
+func (s LinearScorer) Score(facts *Facts) types.ScoreBreakdown {
+	breakdown := types.ScoreBreakdown{Model: s.name}
+	if s.bias != 0 {
+		addComponent(&breakdown, "bias", 1, s.bias)
+	}
+	for _, term := range s.terms {
+		value, _ := facts.Value(term.Field)
+		if term.Cap != 0 {
+			value = math.Min(value, term.Cap)
+		}
+		addComponent(&breakdown, term.Field, value, term.Weight)
+	}
+	breakdown.Total = total(breakdown)
+	return breakdown
+}
+
+// ratio is value as a multiple of minimum, capped at max. A zero minimum is
+// met in full by any positive value.
+func ratio(value, minimum, max float64) float64 {
+	if minimum <= 0 {
+		if value > 0 {
+			return max
+		}
+		return 0
+	}
+	return math.Min(value/minimum, max)
+}
+
+func addComponent(breakdown *types.ScoreBreakdown, name string, value, weight float64) {
+	breakdown.Components = append(breakdown.Components, types.ScoreComponent{
+		Name:         name,
+		Value:        value,
+		Weight:       weight,
+		Contribution: value * weight,
+	})
+}
+
+func addMultiplier(breakdown *types.ScoreBreakdown, name string, value, factor float64) {
+	breakdown.Multipliers = append(breakdown.Multipliers, types.ScoreComponent{
+		Name:         name,
+		Value:        value,
+		Weight:       factor,
+		Contribution: factor,
+	})
+}
+
+func total(breakdown types.ScoreBreakdown) float64 {
+	sum := 0.0
+	for _, component := range breakdown.Components {
+		sum += component.Contribution
+	}
+	product := 1.0
+	for _, multiplier := range breakdown.Multipliers {
+		product *= multiplier.Contribution
+	}
+	return sum * product
+}
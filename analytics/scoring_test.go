@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func scoringFacts() *Facts {
+	facts := NewFacts()
+	facts.Set(FieldLiquidity, 20000, "raydium")
+	facts.Set(FieldVolume24h, 30000, "raydium")
+	facts.Set(FieldMarketCap, 250000, "raydium")
+	facts.Set(FieldHolderCount, 150, "solscan")
+	facts.Set(FieldTopHolderShare, 0.1, "solscan")
+	facts.SetBool(FieldLiquidityLocked, true, "goplus")
+	facts.SetBool(FieldHasWebsite, true, "social")
+	facts.SetBool(FieldHasGitHub, false, "social")
+	facts.SetBool(FieldHasWhitepaper, true, "social")
+	return facts
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestHeuristicScorer(t *testing.T) {
+	thresholds := TokenAnalyzerConfig{MinLiquidity: 10000, MaxMarketCap: 1000000, MinHolderCount: 100}
+	score := NewHeuristicScorer("heuristic", thresholds).Score(scoringFacts())
+
+	// liquidity 2x the minimum * 20, volume ratio 1.5 * 50, 75% room to grow * 30
+	sum := 2.0*20 + 1.5*50 + 0.75*30
+	// 1.5x the minimum holders, no top holder penalty, locked, two of three social links
+	product := 1.5 * 1.0 * 1.2 * 1.2
+	if !closeTo(score.Total, sum*product) {
+		t.Errorf("total = %g, want %g", score.Total, sum*product)
+	}
+	if score.Model != "heuristic" || len(score.Components) != 3 || len(score.Multipliers) != 4 {
+		t.Errorf("breakdown = %+v", score)
+	}
+}
+
+func TestHeuristicScorerUsesAnalyzerThresholds(t *testing.T) {
+	facts := scoringFacts()
+	strict := NewHeuristicScorer("strict", TokenAnalyzerConfig{MinLiquidity: 40000}).Score(facts)
+	loose := NewHeuristicScorer("loose", TokenAnalyzerConfig{MinLiquidity: 2000}).Score(facts)
+
+	if got := strict.Components[0].Value; !closeTo(got, 0.5) {
+		t.Errorf("liquidity against a 40000 minimum = %g, want 0.5", got)
+	}
+	if got := loose.Components[0].Value; got != 5 {
+		t.Errorf("liquidity against a 2000 minimum = %g, want the cap of 5", got)
+	}
+
+	// Zero thresholds are met in full by any positive value, and a zero
+	// maximum market cap gives nothing for room to grow
+	zero := HeuristicScorer{}.Score(facts)
+	if zero.Model != ModelHeuristic || zero.Components[0].Value != 5 || zero.Components[2].Value != 0 ||
+		zero.Multipliers[0].Weight != 2 || math.IsNaN(zero.Total) || math.IsInf(zero.Total, 0) {
+		t.Errorf("zero value scored %+v", zero)
+	}
+}
+
+func TestHeuristicScorerPenalties(t *testing.T) {
+	facts := NewFacts()
+	facts.Set(FieldLiquidity, 10000, "raydium")
+	facts.Set(FieldMarketCap, 5000000, "raydium")
+	facts.Set(FieldHolderCount, 100, "solscan")
+	facts.Set(FieldTopHolderShare, 0.6, "solscan")
+
+	score := NewHeuristicScorer(ModelHeuristic, testThresholds).Score(facts)
+	// Market cap past the maximum gives nothing rather than going negative;
+	// a top holder over half halves the score
+	if !closeTo(score.Total, 1.0*20*0.5) {
+		t.Errorf("total = %g, want %g", score.Total, 1.0*20*0.5)
+	}
+}
+
+func TestLinearScorer(t *testing.T) {
+	scorers, err := NewScorers(ScoringConfig{Models: []ScoringModel{{
+		Name: "linear-v1",
+		Type: ModelLinear,
+		Bias: 5,
+		Terms: []LinearTerm{
+			{Field: FieldLiquidity, Weight: 0.001, Cap: 15000},
+			{Field: FieldTopHolderShare, Weight: -100},
+			{Field: FieldSocialScore, Weight: 10},
+		},
+	}}}, TokenAnalyzerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	score := scorers[0].Score(scoringFacts())
+	// bias + capped liquidity - top holder; social score was never measured
+	want := 5 + 15 - 10.0
+	if score.Model != "linear-v1" || !closeTo(score.Total, want) {
+		t.Errorf("got %s %g, want linear-v1 %g", score.Model, score.Total, want)
+	}
+	if len(score.Multipliers) != 0 || len(score.Components) != 4 {
+		t.Errorf("breakdown = %+v", score)
+	}
+}
+
+func TestNewScorers(t *testing.T) {
+	scorers, err := NewScorers(ScoringConfig{}, TokenAnalyzerConfig{})
+	if err != nil || len(scorers) != 1 || scorers[0].Name() != ModelHeuristic {
+		t.Errorf("without models got %v, %v; want the heuristic alone", scorers, err)
+	}
+
+	scorers, err = NewScorers(ScoringConfig{Primary: "linear-v1", Models: []ScoringModel{
+		{Name: "heuristic", Type: ModelHeuristic},
+		{Name: "linear-v1", Type: ModelLinear},
+	}}, TokenAnalyzerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := []string{scorers[0].Name(), scorers[1].Name()}; names[0] != "linear-v1" || names[1] != "heuristic" {
+		t.Errorf("order = %v, want the primary first", names)
+	}
+
+	for _, test := range []struct {
+		name   string
+		config ScoringConfig
+		error  string
+	}{
+		{"no name", ScoringConfig{Models: []ScoringModel{{Type: ModelHeuristic}}}, "no name"},
+		{"duplicate", ScoringConfig{Models: []ScoringModel{{Name: "a", Type: ModelHeuristic}, {Name: "a", Type: ModelLinear}}}, "duplicate"},
+		{"unknown type", ScoringConfig{Models: []ScoringModel{{Name: "a", Type: "neural"}}}, `unknown type "neural"`},
+		{"unknown field", ScoringConfig{Models: []ScoringModel{{Name: "a", Type: ModelLinear, Terms: []LinearTerm{{Field: "hype"}}}}}, `unknown field "hype"`},
+		{"undefined primary", ScoringConfig{Primary: "b", Models: []ScoringModel{{Name: "a", Type: ModelHeuristic}}}, "primary scoring model b"},
+	} {
+		_, err := NewScorers(test.config, TokenAnalyzerConfig{})
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("%s: got %v, want an error with %q", test.name, err, test.error)
+		}
+	}
+}
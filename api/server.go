@@ -0,0 +1,237 @@
+// Package api serves what the scanner has learned over a local HTTP/JSON API.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"grind/events"
+	"grind/logging"
+	"grind/services"
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	DEFAULT_REJECTIONS_LIMIT = 50
+	MAX_REJECTIONS_LIMIT     = 500
+)
+
+var apiLog = logging.For("api")
+
+// Store is the recorded history the API reads from.
+type Store interface {
+	LatestObservation(mint string) (*types.TokenObservation, error)
+	LatestSafetyReport(mint string) (*types.SafetyReport, error)
+	LatestScores(mint string) ([]types.ScoreBreakdown, error)
+	RecentRejections(limit int) ([]types.SafetyReport, error)
+}
+
+type Server struct {
+	store     Store
+	tracker   *services.TokenTracker
+	positions *services.PositionBook
+	bus       *events.Bus
+	mux       *http.ServeMux
+}
+
+func NewServer(store Store, tracker *services.TokenTracker, positions *services.PositionBook, bus *events.Bus) *Server {
+	s := &Server{store: store, tracker: tracker, positions: positions, bus: bus, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/api/tokens", s.handleTokens)
+	s.mux.HandleFunc("/api/tokens/", s.handleToken)
+	s.mux.HandleFunc("/api/rejections", s.handleRejections)
+	s.mux.HandleFunc("/api/positions", s.handlePositions)
+	s.mux.HandleFunc("/api/check/", s.handleCheck)
+	s.mux.HandleFunc("/api/events", s.handleEvents)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Serve listens on addr until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	// No write timeout since event streams stay open; they end with ctx instead
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// TokenView is everything known about one token.
+type TokenView struct {
+	Mint    string                  `json:"mint"`
+	Tracked bool                    `json:"tracked"`
+	Pair    *types.RaydiumPair      `json:"pair,omitempty"`
+	Latest  *types.TokenObservation `json:"latest,omitempty"` // Metrics and safety inputs
+	Report  *types.SafetyReport     `json:"report,omitempty"`
+	Scores  []types.ScoreBreakdown  `json:"scores"`
+}
+
+// Rejection is a token the filter turned down and the checks that did it.
+type Rejection struct {
+	Mint        string              `json:"mint"`
+	Symbol      string              `json:"symbol,omitempty"`
+	RejectedAt  time.Time           `json:"rejectedAt"`
+	Reasons     []types.SafetyCheck `json:"reasons"`
+	Explanation string              `json:"explanation"`
+}
+
+// GET /api/tokens
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.tracker.Tracked())
+}
+
+// GET /api/tokens/{mint}
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	mint, ok := mintFromPath(w, r, "/api/tokens/")
+	if !ok {
+		return
+	}
+
+	view := TokenView{Mint: mint}
+	if pair, tracked := s.tracker.Get(mint); tracked {
+		view.Tracked = true
+		view.Pair = &pair
+	}
+
+	var err error
+	if view.Latest, err = s.store.LatestObservation(mint); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if view.Report, err = s.store.LatestSafetyReport(mint); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if view.Scores, err = s.store.LatestScores(mint); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !view.Tracked && view.Latest == nil && view.Report == nil && len(view.Scores) == 0 {
+		writeError(w, http.StatusNotFound, errors.New("token has not been seen"))
+		return
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+// GET /api/rejections?limit=N
+func (s *Server) handleRejections(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	limit := DEFAULT_REJECTIONS_LIMIT
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, errors.New("limit must be a positive integer"))
+			return
+		}
+		limit = min(parsed, MAX_REJECTIONS_LIMIT)
+	}
+
+	reports, err := s.store.RecentRejections(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	rejections := make([]Rejection, 0, len(reports))
+	for _, report := range reports {
+		rejections = append(rejections, Rejection{
+			Mint:        report.Mint,
+			Symbol:      report.Symbol,
+			RejectedAt:  report.GeneratedAt,
+			Reasons:     report.BlockingChecks(),
+			Explanation: report.Summary(),
+		})
+	}
+	writeJSON(w, http.StatusOK, rejections)
+}
+
+// GET /api/positions
+func (s *Server) handlePositions(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.positions.All())
+}
+
+// POST /api/check/{mint} runs the safety checks now, for any mint.
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	mint, ok := mintFromPath(w, r, "/api/check/")
+	if !ok {
+		return
+	}
+
+	apiLog.Info("On-demand safety check", "mint", mint)
+	writeJSON(w, http.StatusOK, services.RunSafetyChecks(mint))
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	return false
+}
+
+// mintFromPath takes the mint that follows prefix and rejects anything that
+// is not a valid address.
+func mintFromPath(w http.ResponseWriter, r *http.Request, prefix string) (string, bool) {
+	mint := strings.TrimPrefix(r.URL.Path, prefix)
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid mint address"))
+		return "", false
+	}
+	return mint, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		apiLog.Warn("Failed to write response", logging.Err(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		apiLog.Error("Request failed", logging.Err(err))
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grind/events"
+	"grind/services"
+	"grind/types"
+)
+
+const (
+	sampleMint = "7GCihgDB8fe6KNjn2MYtkzZcRjQy3t9GHdC8uHYmW2hr"
+	otherMint  = "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm"
+)
+
+// stubStore serves fixed history and records the rejections limit asked for.
+type stubStore struct {
+	observations map[string]*types.TokenObservation
+	err          error
+	limit        int
+}
+
+func (s *stubStore) LatestObservation(mint string) (*types.TokenObservation, error) {
+	return s.observations[mint], s.err
+}
+
+func (s *stubStore) LatestSafetyReport(mint string) (*types.SafetyReport, error) {
+	return nil, s.err
+}
+
+func (s *stubStore) LatestScores(mint string) ([]types.ScoreBreakdown, error) {
+	return nil, s.err
+}
+
+func (s *stubStore) RecentRejections(limit int) ([]types.SafetyReport, error) {
+	s.limit = limit
+	report := types.NewSafetyReport(sampleMint, "POPCAT")
+	report.Add(types.SafetyCheck{Name: "liquidity", Status: types.CheckFail, Message: "liquidity: liquidity is 900, want >= 10000"})
+	return []types.SafetyReport{*report}, s.err
+}
+
+func newTestServer(store *stubStore) (*Server, *services.TokenTracker, *services.PositionBook) {
+	tracker := services.NewTokenTracker("")
+	positions := services.NewPositionBook("")
+	return NewServer(store, tracker, positions, events.NewBus()), tracker, positions
+}
+
+func serve(server *Server, method, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+	return recorder
+}
+
+func TestMethodChecks(t *testing.T) {
+	server, _, _ := newTestServer(&stubStore{})
+
+	for _, test := range []struct {
+		method, target, allow string
+	}{
+		{http.MethodPost, "/api/tokens", http.MethodGet},
+		{http.MethodDelete, "/api/tokens/" + sampleMint, http.MethodGet},
+		{http.MethodPut, "/api/rejections", http.MethodGet},
+		{http.MethodPost, "/api/positions", http.MethodGet},
+		{http.MethodGet, "/api/check/" + sampleMint, http.MethodPost},
+	} {
+		response := serve(server, test.method, test.target)
+		if response.Code != http.StatusMethodNotAllowed || response.Header().Get("Allow") != test.allow {
+			t.Errorf("%s %s: got %d allowing %q, want 405 allowing %s", test.method, test.target,
+				response.Code, response.Header().Get("Allow"), test.allow)
+		}
+	}
+}
+
+func TestMintValidation(t *testing.T) {
+	server, _, _ := newTestServer(&stubStore{})
+
+	for _, test := range []struct {
+		method, target string
+	}{
+		{http.MethodGet, "/api/tokens/not-a-mint"},
+		{http.MethodGet, "/api/tokens/"},
+		{http.MethodGet, "/api/tokens/" + sampleMint + "/extra"},
+		{http.MethodPost, "/api/check/0OIl"},
+	} {
+		response := serve(server, test.method, test.target)
+		if response.Code != http.StatusBadRequest || !strings.Contains(response.Body.String(), "invalid mint address") {
+			t.Errorf("%s %s: got %d %s", test.method, test.target, response.Code, response.Body)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	store := &stubStore{observations: map[string]*types.TokenObservation{
+		otherMint: {Pair: types.RaydiumPair{Address: otherMint}},
+	}}
+	server, tracker, _ := newTestServer(store)
+	tracker.Add(types.RaydiumPair{Address: sampleMint, Symbol: "POPCAT"})
+
+	response := serve(server, http.MethodGet, "/api/tokens/"+sampleMint)
+	var view TokenView
+	if err := json.Unmarshal(response.Body.Bytes(), &view); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("tracked token: got %d %s", response.Code, response.Body)
+	}
+	if !view.Tracked || view.Pair == nil || view.Pair.Symbol != "POPCAT" {
+		t.Errorf("tracked token view = %+v", view)
+	}
+
+	response = serve(server, http.MethodGet, "/api/tokens/"+otherMint)
+	view = TokenView{}
+	if err := json.Unmarshal(response.Body.Bytes(), &view); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("recorded token: got %d %s", response.Code, response.Body)
+	}
+	if view.Tracked || view.Latest == nil {
+		t.Errorf("recorded token view = %+v", view)
+	}
+
+	// A valid mint the scanner has never seen
+	response = serve(server, http.MethodGet, "/api/tokens/"+services.SWAP_PROGRAM_ID.String())
+	if response.Code != http.StatusNotFound || !strings.Contains(response.Body.String(), "not been seen") {
+		t.Errorf("unseen token: got %d %s", response.Code, response.Body)
+	}
+
+	store.err = errors.New("database is locked")
+	if response := serve(server, http.MethodGet, "/api/tokens/"+otherMint); response.Code != http.StatusInternalServerError {
+		t.Errorf("store failure: got %d %s", response.Code, response.Body)
+	}
+}
+
+func TestRejectionsLimit(t *testing.T) {
+	store := &stubStore{}
+	server, _, _ := newTestServer(store)
+
+	for _, test := range []struct {
+		query  string
+		status int
+		limit  int
+	}{
+		{"", http.StatusOK, DEFAULT_REJECTIONS_LIMIT},
+		{"?limit=10", http.StatusOK, 10},
+		{"?limit=100000", http.StatusOK, MAX_REJECTIONS_LIMIT},
+		{"?limit=0", http.StatusBadRequest, 0},
+		{"?limit=-5", http.StatusBadRequest, 0},
+		{"?limit=ten", http.StatusBadRequest, 0},
+	} {
+		store.limit = 0
+		response := serve(server, http.MethodGet, "/api/rejections"+test.query)
+		if response.Code != test.status || store.limit != test.limit {
+			t.Errorf("%q: got %d with limit %d, want %d with limit %d", test.query, response.Code, store.limit, test.status, test.limit)
+		}
+	}
+
+	response := serve(server, http.MethodGet, "/api/rejections")
+	var rejections []Rejection
+	if err := json.Unmarshal(response.Body.Bytes(), &rejections); err != nil {
+		t.Fatal(err)
+	}
+	if len(rejections) != 1 || len(rejections[0].Reasons) != 1 || rejections[0].Reasons[0].Name != "liquidity" {
+		t.Errorf("rejections = %+v", rejections)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	server, _, positions := newTestServer(&stubStore{})
+
+	response := serve(server, http.MethodGet, "/api/positions")
+	if response.Code != http.StatusOK || strings.TrimSpace(response.Body.String()) != "[]" {
+		t.Errorf("no positions: got %d %s", response.Code, response.Body)
+	}
+
+	positions.Open(types.Position{Mint: sampleMint, Symbol: "POPCAT", Amount: 1000, EntryPrice: 0.5, OpenedAt: time.Now()})
+	response = serve(server, http.MethodGet, "/api/positions")
+	var got []types.Position
+	if err := json.Unmarshal(response.Body.Bytes(), &got); err != nil || len(got) != 1 || got[0].Amount != 1000 {
+		t.Errorf("got %s, %v", response.Body, err)
+	}
+}
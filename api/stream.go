@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"grind/events"
+	"grind/logging"
+)
+
+// HEARTBEAT_INTERVAL keeps idle streams from being closed by proxies.
+const HEARTBEAT_INTERVAL = 15 * time.Second
+
+// GET /api/events?kinds=filter_passed,rug_alert streams pipeline events as
+// server-sent events. A reconnecting client that sends Last-Event-ID first
+// gets the recent events it missed.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	var kinds []events.Kind
+	if value := r.URL.Query().Get("kinds"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			kind := events.Kind(strings.TrimSpace(name))
+			if !kind.Valid() {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("unknown event kind %q", kind))
+				return
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+
+	var lastID uint64
+	if value := r.Header.Get("Last-Event-ID"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid Last-Event-ID"))
+			return
+		}
+		lastID = parsed
+	}
+
+	stream, cancel := s.bus.Subscribe(lastID, kinds...)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-stream:
+			data, err := json.Marshal(event)
+			if err != nil {
+				apiLog.Warn("Failed to encode event", "kind", event.Kind, logging.Err(err))
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Kind, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
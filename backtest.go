@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"grind/backtest"
+	"grind/db"
+	"log"
+	"os"
+	"time"
+)
+
+func runBacktest(args []string) error {
+	flags := flag.NewFlagSet("backtest", flag.ExitOnError)
+	configPath := flags.String("config", "backtest.json", "configurations to compare")
+	fixturePath := flags.String("fixture", "", "replay a fixture file instead of the database")
+	dbPath := flags.String("db", "grind.db", "database with recorded observations")
+	days := flags.Int("days", 7, "days of recorded observations to replay")
+	jsonOutput := flags.Bool("json", false, "same as -format json")
+	outputFormat := formatFlag(flags, FORMAT_TABLE, FORMAT_JSON)
+	flags.Parse(args)
+
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		format = FORMAT_JSON
+	}
+
+	config, err := backtest.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load backtest config: %w", err)
+	}
+
+	var dataset *backtest.Dataset
+	if *fixturePath != "" {
+		dataset, err = backtest.LoadFixture(*fixturePath)
+	} else {
+		var database *db.SQLiteDB
+		database, err = db.NewDatabase(*dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer database.Close()
+
+		until := time.Now()
+		dataset, err = backtest.LoadFromStore(database, until.AddDate(0, 0, -*days), until)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load backtest data: %w", err)
+	}
+	log.Printf("Replaying %d observations across %d configurations", len(dataset.Observations), len(config.Configurations))
+
+	results := make([]*backtest.Result, 0, len(config.Configurations))
+	for _, configuration := range config.Configurations {
+		result, err := backtest.Run(dataset, configuration)
+		if err != nil {
+			return fmt.Errorf("backtest failed: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if format == FORMAT_JSON {
+		return printResult(os.Stdout, format, results, nil)
+	}
+	backtest.PrintResults(os.Stdout, results)
+	return nil
+}
@@ -0,0 +1,76 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"grind/analytics"
+	"grind/config"
+)
+
+// Strategy decides how a simulated position is entered and exited.
+type Strategy struct {
+	PositionSize   float64 `json:"positionSize"`   // SOL per entry
+	MinScore       float64 `json:"minScore"`       // Primary model score required to enter
+	TakeProfit     float64 `json:"takeProfit"`     // Exit at +TakeProfit, e.g. 1.0 for +100%
+	StopLoss       float64 `json:"stopLoss"`       // Exit at -StopLoss, e.g. 0.3 for -30%
+	MaxHoldMinutes int     `json:"maxHoldMinutes"` // Exit at market after this long
+}
+
+func (s Strategy) MaxHold() time.Duration {
+	return time.Duration(s.MaxHoldMinutes) * time.Minute
+}
+
+// Configuration is one set of rules, scoring models and strategy to replay.
+type Configuration struct {
+	Name     string                        `json:"name"`
+	Analyzer analytics.TokenAnalyzerConfig `json:"analyzer"`
+	Scoring  analytics.ScoringConfig       `json:"scoring"`
+	Strategy Strategy                      `json:"strategy"`
+}
+
+// UnmarshalJSON starts the analyzer from the scanner's default thresholds, so
+// a configuration only lists the ones it changes.
+func (c *Configuration) UnmarshalJSON(data []byte) error {
+	type plain Configuration
+	defaults := config.Default()
+	configuration := plain{Analyzer: defaults.AnalyzerConfig()}
+	if err := json.Unmarshal(data, &configuration); err != nil {
+		return err
+	}
+	*c = Configuration(configuration)
+	return nil
+}
+
+type Config struct {
+	Configurations []Configuration `json:"configurations"`
+}
+
+func LoadConfig(filepath string) (*Config, error) {
+	file, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, err
+	}
+
+	if len(config.Configurations) == 0 {
+		return nil, fmt.Errorf("no configurations in %s", filepath)
+	}
+	for i := range config.Configurations {
+		strategy := &config.Configurations[i].Strategy
+		if strategy.PositionSize == 0 {
+			strategy.PositionSize = 1
+		}
+		if strategy.MaxHoldMinutes == 0 {
+			strategy.MaxHoldMinutes = 24 * 60
+		}
+	}
+
+	return &config, nil
+}
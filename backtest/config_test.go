@@ -0,0 +1,30 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"grind/config"
+)
+
+func TestLoadConfigAnalyzerDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backtest.json")
+	data := `{"configurations": [{"name": "no-minimums", "analyzer": {"minLiquidity": 0, "minHolders": 0}}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := config.Default()
+	want := defaults.AnalyzerConfig()
+	want.MinLiquidity, want.MinHolderCount = 0, 0
+	if got := loaded.Configurations[0].Analyzer; got.MaxMarketCap != want.MaxMarketCap ||
+		got.MaxTopHolder != want.MaxTopHolder || got.MinLockTime != want.MinLockTime ||
+		got.MinLiquidity != 0 || got.MinHolderCount != 0 {
+		t.Errorf("analyzer = %+v, want the defaults with the explicit zeros kept: %+v", got, want)
+	}
+}
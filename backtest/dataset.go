@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"grind/types"
+)
+
+// Dataset is the recorded candidate stream and the price history of each mint.
+type Dataset struct {
+	Observations []types.TokenObservation      `json:"observations"`
+	Prices       map[string][]types.PricePoint `json:"prices"`
+}
+
+// Store is where recorded observations and prices are read from.
+type Store interface {
+	LoadObservations(since, until time.Time) ([]types.TokenObservation, error)
+	LoadPriceSeries(mint string) ([]types.PricePoint, error)
+}
+
+func LoadFixture(filepath string) (*Dataset, error) {
+	file, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataset Dataset
+	if err := json.Unmarshal(file, &dataset); err != nil {
+		return nil, err
+	}
+	dataset.sort()
+	return &dataset, nil
+}
+
+func LoadFromStore(store Store, since, until time.Time) (*Dataset, error) {
+	observations, err := store.LoadObservations(since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{
+		Observations: observations,
+		Prices:       make(map[string][]types.PricePoint),
+	}
+	for _, observation := range observations {
+		mint := observation.Pair.Address
+		if _, ok := dataset.Prices[mint]; ok {
+			continue
+		}
+		series, err := store.LoadPriceSeries(mint)
+		if err != nil {
+			return nil, err
+		}
+		dataset.Prices[mint] = series
+	}
+	dataset.sort()
+	return dataset, nil
+}
+
+func (d *Dataset) sort() {
+	sort.SliceStable(d.Observations, func(i, j int) bool {
+		return d.Observations[i].ObservedAt.Before(d.Observations[j].ObservedAt)
+	})
+	for _, series := range d.Prices {
+		sort.SliceStable(series, func(i, j int) bool {
+			return series[i].Time.Before(series[j].Time)
+		})
+	}
+}
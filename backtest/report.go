@@ -0,0 +1,44 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// PrintResults writes one summary row per configuration, then each
+// configuration's rejection reasons.
+func PrintResults(w io.Writer, results []*Result) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CONFIGURATION\tCANDIDATES\tPASSED\tTRADES\tHIT RATE\tTOTAL PNL\tMAX DD\tMIN\tP25\tMEDIAN\tP75\tMAX")
+	for _, result := range results {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%.1f%%\t%.4f\t%.4f\t%+.1f%%\t%+.1f%%\t%+.1f%%\t%+.1f%%\t%+.1f%%\n",
+			result.Configuration, result.Candidates, result.Passed, len(result.Trades),
+			result.HitRate*100, result.TotalPnL, result.MaxDrawdown,
+			result.Returns.Min*100, result.Returns.P25*100, result.Returns.Median*100,
+			result.Returns.P75*100, result.Returns.Max*100)
+	}
+	table.Flush()
+
+	for _, result := range results {
+		if len(result.Rejections) == 0 && result.NoPriceData == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s rejections:\n", result.Configuration)
+
+		reasons := make([]string, 0, len(result.Rejections))
+		for reason := range result.Rejections {
+			reasons = append(reasons, reason)
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			return result.Rejections[reasons[i]] > result.Rejections[reasons[j]]
+		})
+		for _, reason := range reasons {
+			fmt.Fprintf(w, "- %s: %d\n", reason, result.Rejections[reason])
+		}
+		if result.NoPriceData > 0 {
+			fmt.Fprintf(w, "- passed without price data: %d\n", result.NoPriceData)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"grind/analytics"
+	"grind/services"
+	"grind/types"
+)
+
+const (
+	ExitTakeProfit = "take_profit"
+	ExitStopLoss   = "stop_loss"
+	ExitMaxHold    = "max_hold"
+	ExitEndOfData  = "end_of_data"
+)
+
+type Trade struct {
+	Mint       string    `json:"mint"`
+	Symbol     string    `json:"symbol"`
+	Score      float64   `json:"score"`
+	EntryTime  time.Time `json:"entryTime"`
+	EntryPrice float64   `json:"entryPrice"`
+	ExitTime   time.Time `json:"exitTime"`
+	ExitPrice  float64   `json:"exitPrice"`
+	ExitReason string    `json:"exitReason"`
+	Return     float64   `json:"return"` // Fractional, e.g. 0.5 for +50%
+	PnL        float64   `json:"pnl"`    // SOL
+}
+
+type Result struct {
+	Configuration string         `json:"configuration"`
+	Candidates    int            `json:"candidates"`
+	Passed        int            `json:"passed"`
+	Rejections    map[string]int `json:"rejections"`
+	NoPriceData   int            `json:"noPriceData"`
+	Trades        []Trade        `json:"trades"`
+	HitRate       float64        `json:"hitRate"`
+	TotalPnL      float64        `json:"totalPnl"`
+	MaxDrawdown   float64        `json:"maxDrawdown"` // SOL, peak to trough of cumulative PnL
+	Returns       Distribution   `json:"returns"`
+}
+
+type Distribution struct {
+	Min    float64 `json:"min"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+}
+
+// Run replays the dataset through the configuration's rules and primary
+// scoring model. A mint is judged until it passes once, and is entered at
+// most once.
+func Run(dataset *Dataset, config Configuration) (*Result, error) {
+	analyzer, err := analytics.NewTokenAnalyzer(config.Analyzer)
+	if err != nil {
+		return nil, fmt.Errorf("configuration %s: %w", config.Name, err)
+	}
+	scorers, err := analytics.NewScorers(config.Scoring, config.Analyzer)
+	if err != nil {
+		return nil, fmt.Errorf("configuration %s: %w", config.Name, err)
+	}
+
+	result := &Result{
+		Configuration: config.Name,
+		Rejections:    make(map[string]int),
+		Trades:        make([]Trade, 0),
+	}
+	entered := make(map[string]bool)
+
+	for _, observation := range dataset.Observations {
+		mint := observation.Pair.Address
+		if entered[mint] {
+			continue
+		}
+		result.Candidates++
+
+		facts := services.TokenFactsAt(observation.Pair, observation.Metrics, observation.Safety, observation.ObservedAt)
+		report := analyzer.Evaluate(mint, observation.Pair.Symbol, facts)
+		if !report.Passed() {
+			for _, check := range report.BlockingChecks() {
+				result.Rejections[check.Name]++
+			}
+			continue
+		}
+
+		score := scorers[0].Score(facts).Total
+		if score < config.Strategy.MinScore {
+			result.Rejections["min_score"]++
+			continue
+		}
+		result.Passed++
+		entered[mint] = true
+
+		trade, ok := simulateTrade(dataset.Prices[mint], observation.ObservedAt, config.Strategy)
+		if !ok {
+			result.NoPriceData++
+			continue
+		}
+		trade.Mint = mint
+		trade.Symbol = observation.Pair.Symbol
+		trade.Score = score
+		result.Trades = append(result.Trades, trade)
+	}
+
+	summarize(result)
+	return result, nil
+}
+
+// simulateTrade enters at the first price at or after the observation and
+// exits on take profit, stop loss, max hold or the end of the series. Points
+// without a positive price are gaps in the data and skipped.
+func simulateTrade(series []types.PricePoint, observedAt time.Time, strategy Strategy) (Trade, bool) {
+	start := sort.Search(len(series), func(i int) bool {
+		return !series[i].Time.Before(observedAt)
+	})
+	for start < len(series) && series[start].Price <= 0 {
+		start++
+	}
+	if start >= len(series) {
+		return Trade{}, false
+	}
+
+	entry := series[start]
+	trade := Trade{EntryTime: entry.Time, EntryPrice: entry.Price}
+	deadline := entry.Time.Add(strategy.MaxHold())
+
+	exit := entry
+	trade.ExitReason = ExitEndOfData
+	for _, point := range series[start+1:] {
+		if point.Price <= 0 {
+			continue
+		}
+		exit = point
+		change := point.Price/entry.Price - 1
+		if strategy.TakeProfit > 0 && change >= strategy.TakeProfit {
+			trade.ExitReason = ExitTakeProfit
+			break
+		}
+		if strategy.StopLoss > 0 && change <= -strategy.StopLoss {
+			trade.ExitReason = ExitStopLoss
+			break
+		}
+		if point.Time.After(deadline) {
+			trade.ExitReason = ExitMaxHold
+			break
+		}
+	}
+
+	trade.ExitTime = exit.Time
+	trade.ExitPrice = exit.Price
+	trade.Return = exit.Price/entry.Price - 1
+	trade.PnL = trade.Return * strategy.PositionSize
+	return trade, true
+}
+
+func summarize(result *Result) {
+	if len(result.Trades) == 0 {
+		return
+	}
+
+	returns := make([]float64, 0, len(result.Trades))
+	wins := 0
+	for _, trade := range result.Trades {
+		returns = append(returns, trade.Return)
+		result.TotalPnL += trade.PnL
+		if trade.PnL > 0 {
+			wins++
+		}
+	}
+	result.HitRate = float64(wins) / float64(len(result.Trades))
+	result.Returns = distribution(returns)
+
+	// Drawdown of the equity curve, realising trades in exit order
+	byExit := append([]Trade(nil), result.Trades...)
+	sort.Slice(byExit, func(i, j int) bool {
+		return byExit[i].ExitTime.Before(byExit[j].ExitTime)
+	})
+	equity, peak := 0.0, 0.0
+	for _, trade := range byExit {
+		equity += trade.PnL
+		peak = math.Max(peak, equity)
+		result.MaxDrawdown = math.Max(result.MaxDrawdown, peak-equity)
+	}
+}
+
+func distribution(values []float64) Distribution {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	return Distribution{
+		Min:    sorted[0],
+		P25:    percentile(sorted, 0.25),
+		Median: percentile(sorted, 0.5),
+		P75:    percentile(sorted, 0.75),
+		Max:    sorted[len(sorted)-1],
+		Mean:   sum / float64(len(sorted)),
+	}
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	index := p * float64(len(sorted)-1)
+	lower := int(math.Floor(index))
+	upper := int(math.Ceil(index))
+	weight := index - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
@@ -0,0 +1,145 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"grind/analytics"
+	"grind/types"
+)
+
+var start = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func series(prices ...float64) []types.PricePoint {
+	points := make([]types.PricePoint, len(prices))
+	for i, price := range prices {
+		points[i] = types.PricePoint{Time: start.Add(time.Duration(i) * time.Minute), Price: price}
+	}
+	return points
+}
+
+func TestSimulateTrade(t *testing.T) {
+	strategy := Strategy{PositionSize: 0.5, TakeProfit: 1, StopLoss: 0.3, MaxHoldMinutes: 3}
+
+	for _, test := range []struct {
+		name       string
+		series     []types.PricePoint
+		observedAt time.Time
+		ok         bool
+		entry      float64
+		exit       float64
+		reason     string
+	}{
+		{"take profit", series(1, 1.5, 2.1, 3), start, true, 1, 2.1, ExitTakeProfit},
+		{"stop loss", series(1, 0.9, 0.6, 2), start, true, 1, 0.6, ExitStopLoss},
+		{"max hold", series(1, 1.1, 1.2, 1.1, 1.3, 5), start, true, 1, 1.3, ExitMaxHold},
+		{"end of data", series(1, 1.2, 1.4), start, true, 1, 1.4, ExitEndOfData},
+		{"entry after observation", series(4, 1, 1.5), start.Add(30 * time.Second), true, 1, 1.5, ExitEndOfData},
+		{"single point", series(2), start, true, 2, 2, ExitEndOfData},
+		{"zero entry skipped", series(0, 0, 1, 2.5), start, true, 1, 2.5, ExitTakeProfit},
+		{"zero exit skipped", series(1, 1.2, 0), start, true, 1, 1.2, ExitEndOfData},
+		{"only zeros", series(0, 0), start, false, 0, 0, ""},
+		{"observed after the series", series(1, 2), start.Add(time.Hour), false, 0, 0, ""},
+		{"no series", nil, start, false, 0, 0, ""},
+	} {
+		trade, ok := simulateTrade(test.series, test.observedAt, strategy)
+		if ok != test.ok {
+			t.Errorf("%s: ok = %v, want %v", test.name, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if trade.EntryPrice != test.entry || trade.ExitPrice != test.exit || trade.ExitReason != test.reason {
+			t.Errorf("%s: got %g -> %g (%s), want %g -> %g (%s)", test.name,
+				trade.EntryPrice, trade.ExitPrice, trade.ExitReason, test.entry, test.exit, test.reason)
+		}
+		want := test.exit/test.entry - 1
+		if math.IsNaN(trade.Return) || math.IsInf(trade.Return, 0) || math.Abs(trade.Return-want) > 1e-9 {
+			t.Errorf("%s: return = %g, want %g", test.name, trade.Return, want)
+		}
+		if math.Abs(trade.PnL-want*strategy.PositionSize) > 1e-9 {
+			t.Errorf("%s: pnl = %g, want %g", test.name, trade.PnL, want*strategy.PositionSize)
+		}
+	}
+}
+
+func observation(mint string, liquidity float64, at time.Time) types.TokenObservation {
+	return types.TokenObservation{
+		Pair:       types.RaydiumPair{Address: mint, Symbol: mint},
+		Metrics:    types.TokenMetrics{Liquidity: liquidity},
+		ObservedAt: at,
+	}
+}
+
+func TestRun(t *testing.T) {
+	dataset := &Dataset{
+		Observations: []types.TokenObservation{
+			observation("winner", 20000, start),
+			observation("loser", 20000, start),
+			observation("thin", 500, start),
+			observation("unpriced", 20000, start),
+			observation("winner", 20000, start.Add(time.Minute)),
+			observation("unpriced", 20000, start.Add(2*time.Minute)),
+			observation("thin", 30000, start.Add(3*time.Minute)),
+		},
+		Prices: map[string][]types.PricePoint{
+			"winner": series(1, 2),
+			"loser":  series(1, 0.5),
+			"thin":   series(1, 1, 1, 1, 1.5),
+		},
+	}
+	config := Configuration{
+		Name: "test",
+		Analyzer: analytics.TokenAnalyzerConfig{Rules: []analytics.Rule{
+			{Name: "liquidity", Field: analytics.FieldLiquidity, Operator: analytics.OpGreaterOrEqual, Threshold: 10000, Severity: analytics.SeverityReject},
+		}},
+		Strategy: Strategy{PositionSize: 1, TakeProfit: 0.5, StopLoss: 0.3, MaxHoldMinutes: 60},
+	}
+
+	result, err := Run(dataset, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second winner observation is skipped once entered, and so is the
+	// second unpriced one once it has passed; thin passes on its second look
+	if result.Candidates != 5 || result.Passed != 4 || result.NoPriceData != 1 {
+		t.Errorf("candidates %d, passed %d, no price data %d; want 5, 4, 1",
+			result.Candidates, result.Passed, result.NoPriceData)
+	}
+	if result.Rejections["liquidity"] != 1 {
+		t.Errorf("rejections = %v, want thin's first look", result.Rejections)
+	}
+	if len(result.Trades) != 3 {
+		t.Fatalf("trades = %+v, want winner, loser and thin", result.Trades)
+	}
+	if math.Abs(result.TotalPnL-(1-0.5+0.5)) > 1e-9 || math.Abs(result.HitRate-2.0/3) > 1e-9 {
+		t.Errorf("pnl %g, hit rate %g; want 1 and 2/3", result.TotalPnL, result.HitRate)
+	}
+	if result.MaxDrawdown != 0.5 {
+		t.Errorf("max drawdown = %g, want 0.5", result.MaxDrawdown)
+	}
+}
+
+func TestRunMinScore(t *testing.T) {
+	dataset := &Dataset{
+		Observations: []types.TokenObservation{observation("a", 20000, start)},
+		Prices:       map[string][]types.PricePoint{"a": series(1, 2)},
+	}
+	config := Configuration{
+		Analyzer: analytics.TokenAnalyzerConfig{Rules: []analytics.Rule{
+			{Name: "liquidity", Field: analytics.FieldLiquidity, Operator: analytics.OpGreaterOrEqual, Threshold: 10000, Severity: analytics.SeverityReject},
+		}},
+		Strategy: Strategy{PositionSize: 1, MinScore: 1e9},
+	}
+
+	result, err := Run(dataset, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed != 0 || result.Rejections["min_score"] != 1 || len(result.Trades) != 0 {
+		t.Errorf("got %+v, want a min_score rejection", result)
+	}
+}
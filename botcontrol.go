@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"grind/notifications"
+	"grind/services"
+	"grind/types"
+	"sort"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// scanControl is what the telegram bot's commands act on while scanning.
+type scanControl struct {
+	tracker   *services.TokenTracker
+	positions *services.PositionBook
+	notifier  *notifiers
+	started   time.Time
+}
+
+func (c *scanControl) Status() notifications.BotStatus {
+	return notifications.BotStatus{
+		Paused:    services.Paused(),
+		Tracked:   len(c.tracker.Tracked()),
+		Positions: len(c.positions.All()),
+		Since:     c.started,
+	}
+}
+
+func (c *scanControl) Positions() []types.Position {
+	positions := c.positions.All()
+	sort.Slice(positions, func(i, j int) bool { return positions[i].OpenedAt.Before(positions[j].OpenedAt) })
+	return positions
+}
+
+func (c *scanControl) Check(mint string) (types.TokenReport, error) {
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		return types.TokenReport{}, fmt.Errorf("invalid mint address: %w", err)
+	}
+	result, err := checkToken(mint, false)
+	if err != nil {
+		return types.TokenReport{}, err
+	}
+
+	token := types.TokenReport{Report: *result.Report, Scores: result.Scores}
+	if result.Pair != nil {
+		token.Pair = *result.Pair
+	}
+	return token, nil
+}
+
+func (c *scanControl) Buy(mint string, sol float64) (types.Trade, error) {
+	attempt := types.Trade{Side: types.TradeBuy, Mint: mint, Amount: sol, Reason: "telegram", Time: time.Now()}
+	pair, err := c.findPair(mint)
+	if err != nil {
+		return failedTrade(attempt, err)
+	}
+	attempt.Symbol = pair.Symbol
+
+	result, err := executeBuy(pair, sol, attempt.Reason, c.positions, c.notifier, func(solana.Signature) {})
+	if err != nil {
+		return failedTrade(attempt, err)
+	}
+	return result.trade(attempt.Reason), nil
+}
+
+// Sell sells percent of the open position in mint.
+func (c *scanControl) Sell(mint string, percent float64) (types.Trade, error) {
+	attempt := types.Trade{Side: types.TradeSell, Mint: mint, Reason: "telegram", Time: time.Now()}
+	position, held := c.positions.Get(mint)
+	if !held {
+		return failedTrade(attempt, fmt.Errorf("no open position in %s", mint))
+	}
+	attempt.Symbol, attempt.Amount = position.Symbol, position.Amount
+	if percent < 100 {
+		attempt.Amount = position.Amount * percent / 100
+	}
+
+	pair, err := c.findPair(mint)
+	if err != nil {
+		return failedTrade(attempt, err)
+	}
+	result, err := executeSell(pair, attempt.Amount, attempt.Reason, c.positions, c.notifier, func(solana.Signature) {})
+	if err != nil {
+		return failedTrade(attempt, err)
+	}
+	return result.trade(attempt.Reason), nil
+}
+
+func (c *scanControl) SetPaused(paused bool) {
+	services.SetPaused(paused)
+}
+
+// findPair prefers the tracked pair, falling back to the pairs feed for
+// tokens the scanner is not tracking.
+func (c *scanControl) findPair(mint string) (services.RaydiumPair, error) {
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		return services.RaydiumPair{}, fmt.Errorf("invalid mint address: %w", err)
+	}
+	if pair, tracked := c.tracker.Get(mint); tracked {
+		return pair, nil
+	}
+	pair, found, err := services.FindPair(mint)
+	if err != nil {
+		return services.RaydiumPair{}, fmt.Errorf("failed to fetch pairs: %w", err)
+	}
+	if !found {
+		return services.RaydiumPair{}, fmt.Errorf("no Raydium pool found for %s", mint)
+	}
+	return pair, nil
+}
+
+func failedTrade(attempt types.Trade, err error) (types.Trade, error) {
+	attempt.Error = err.Error()
+	return attempt, err
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"grind/services"
+	"grind/types"
+	"io"
+	"os"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// checkResult is what `grind check` reports on a token.
+type checkResult struct {
+	Mint    string                 `json:"mint"`
+	Passed  bool                   `json:"passed"`
+	Pair    *services.RaydiumPair  `json:"pair,omitempty"`
+	Metrics *services.TokenMetrics `json:"metrics,omitempty"`
+	Report  *services.SafetyReport `json:"report"`
+	Scores  []types.ScoreBreakdown `json:"scores,omitempty"`
+}
+
+// runCheck runs the safety checks once. A token listed in the pairs feed gets
+// the full analysis, market filters and scores included; any other mint, or
+// any mint with -safety-only, gets the safety rules alone.
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	var source configSource
+	source.register(flags)
+	outputFormat := formatFlag(flags, FORMAT_TABLE, FORMAT_JSON)
+	safetyOnly := flags.Bool("safety-only", false, "skip the pairs feed and run only the safety rules")
+	positional, err := argsAfterFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: grind check [flags] <mint>")
+	}
+	mint := positional[0]
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		return fmt.Errorf("invalid mint address: %w", err)
+	}
+
+	cfg, _, err := source.load()
+	if err != nil {
+		return err
+	}
+	if err := configureServices(cfg); err != nil {
+		return err
+	}
+
+	result, err := checkToken(mint, *safetyOnly)
+	if err != nil {
+		return err
+	}
+
+	return printResult(os.Stdout, format, result, func(w io.Writer) {
+		verdict := "PASSED"
+		if !result.Passed {
+			verdict = "REJECTED"
+		}
+		fmt.Fprintf(w, "%s (%s): %s\n\n", result.Report.Symbol, mint, verdict)
+
+		fmt.Fprintln(w, "CHECK\tSTATUS\tVALUE\tTHRESHOLD\tSOURCE\tMESSAGE")
+		for _, check := range result.Report.Checks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				check.Name, check.Status, check.Value, check.Threshold, check.Source, check.Message)
+		}
+
+		if len(result.Scores) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "MODEL\tSCORE")
+			for _, score := range result.Scores {
+				fmt.Fprintf(w, "%s\t%.2f\n", score.Model, score.Total)
+			}
+		}
+	})
+}
+
+// checkToken runs the full analysis on a mint the pairs feed lists and the
+// safety rules alone on any other.
+func checkToken(mint string, safetyOnly bool) (checkResult, error) {
+	result := checkResult{Mint: mint}
+	if !safetyOnly {
+		pair, found, err := services.FindPair(mint)
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch pairs: %w", err)
+		}
+		if found {
+			metrics, err := services.CachedTokenMetrics(pair)
+			if err != nil {
+				return result, fmt.Errorf("failed to fetch metrics: %w", err)
+			}
+			safety := services.CheckTokenSafety(mint)
+			result.Pair, result.Metrics = &pair, metrics
+			result.Report = services.AnalyzeTokenPotential(pair, *metrics, safety)
+			result.Scores = services.ScoreToken(pair, *metrics, safety)
+		}
+	}
+	if result.Report == nil {
+		result.Report = services.RunSafetyChecks(mint)
+	}
+	result.Passed = result.Report.Passed()
+	return result, nil
+}
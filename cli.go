@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"grind/config"
+	"grind/logging"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FORMAT_TABLE = "table"
+	FORMAT_JSON  = "json"
+	FORMAT_YAML  = "yaml"
+)
+
+// configSource picks the config files, the same way for every command.
+type configSource struct {
+	path    string
+	profile string
+}
+
+func (f *configSource) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.path, "config", os.Getenv("GRIND_CONFIG"), "base config file (default "+CONFIG_PATH+")")
+	flags.StringVar(&f.profile, "profile", os.Getenv("GRIND_PROFILE"), "profile overlay to apply, e.g. paper")
+}
+
+func (f *configSource) files() ([]string, error) {
+	return configPaths(f.path, f.profile)
+}
+
+// load reads and validates the config and sets up logging from it.
+func (f *configSource) load() (*config.Config, []string, error) {
+	files, err := f.files()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg, err := config.LoadConfig(files...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	if err := logging.Setup(cfg.Logging); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, files, nil
+}
+
+// formatFlag is the -format flag, limited to the given formats; the first is the default.
+func formatFlag(flags *flag.FlagSet, formats ...string) func() (string, error) {
+	format := flags.String("format", formats[0], "output format: "+strings.Join(formats, ", "))
+	return func() (string, error) {
+		for _, allowed := range formats {
+			if *format == allowed {
+				return *format, nil
+			}
+		}
+		return "", fmt.Errorf("unknown format %q, expected one of %s", *format, strings.Join(formats, ", "))
+	}
+}
+
+// printResult writes value as indented JSON, or hands a tab-aligned writer to
+// table for the human-readable form.
+func printResult(w io.Writer, format string, value any, table func(w io.Writer)) error {
+	if format == FORMAT_JSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// confirm asks a yes/no question on the terminal; anything but yes is no.
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// argsAfterFlags parses flags wherever they appear, so both
+// `check -format json <mint>` and `check <mint> -format json` work.
+func argsAfterFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -1,29 +1,490 @@
 package config
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"strings"
+	"time"
+
+	"grind/analytics"
+	"grind/logging"
+	"grind/notifications"
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
 )
 
 type Config struct {
-	TelegramBotKey string  `json:"telegramBotKey"`
-	TelegramChatID string  `json:"telegramChatId"`
-	MinLiquidity   float64 `json:"minLiquidity"`
-	MinHolders     int     `json:"minHolders"`
-	MaxTopHolder   float64 `json:"maxTopHolder"`
-	MinLockTime    int64   `json:"minLockTime"`
+	Sources       SourcesConfig           `json:"sources"`
+	RPC           RPCConfig               `json:"rpc"`
+	Safety        SafetyConfig            `json:"safety"`
+	Scoring       analytics.ScoringConfig `json:"scoring"`
+	Trading       TradingConfig           `json:"trading"`
+	Notifications NotificationsConfig     `json:"notifications"`
+	Storage       StorageConfig           `json:"storage"`
+	Logging       logging.Config          `json:"logging"`
+	Metrics       MetricsConfig           `json:"metrics"`
+	API           APIConfig               `json:"api"`
+}
+
+// SourcesConfig covers the market and token data APIs.
+type SourcesConfig struct {
+	RaydiumPairsURL string `json:"raydiumPairsUrl"`
+	RaydiumPoolURL  string `json:"raydiumPoolUrl"`
+	SolscanURL      string `json:"solscanUrl"`
+	GoPlusURL       string `json:"goPlusUrl"`
+	// TwitterBearerToken authenticates follower lookups for social checks
+	TwitterBearerToken    string `json:"twitterBearerToken"`
+	FetchIntervalSeconds  int    `json:"fetchIntervalSeconds"`
+	RequestTimeoutSeconds int    `json:"requestTimeoutSeconds"`
+	// PriceSource is where price samples come from: "pairs" (the Raydium feed) or "reserves" (pool vaults)
+	PriceSource        string `json:"priceSource"`
+	PriceSampleSeconds int    `json:"priceSampleSeconds"`
+	// GoPlusBatchSize is how many tokens go in one GoPlus token_security request
+	GoPlusBatchSize int `json:"goPlusBatchSize"`
+}
+
+type RPCConfig struct {
+	URL          string `json:"url"`
+	WebsocketURL string `json:"websocketUrl"`
 }
 
-func LoadConfig(filepath string) (*Config, error) {
-	file, err := os.ReadFile(filepath)
+// SafetyConfig holds the filtering thresholds and rug detection.
+type SafetyConfig struct {
+	MinLiquidity float64 `json:"minLiquidity"`
+	MaxMarketCap float64 `json:"maxMarketCap"`
+	MinHolders   int     `json:"minHolders"`
+	MaxTopHolder float64 `json:"maxTopHolder"`
+	MinLockTime  int64   `json:"minLockTime"` // Seconds
+	MinAge       int64   `json:"minAge"`      // Seconds
+	MaxTax       float64 `json:"maxTax"`      // Buy or sell tax in percent above which a token is a honeypot
+	// Rules replaces the filtering rules derived from the thresholds above
+	Rules []analytics.Rule `json:"rules"`
+	// FailurePolicies sets, per safety check, whether an unavailable data source rejects the token
+	FailurePolicies map[string]types.CheckPolicy `json:"failurePolicies"`
+	// RugWatch sets when a tracked pool is treated as rugged
+	RugWatch types.RugWatchConfig `json:"rugWatch"`
+}
+
+type TradingConfig struct {
+	WalletAddress string `json:"walletAddress"`
+	// PrivateKey is the wallet's base58 secret key, used to sign swaps
+	PrivateKey        string  `json:"privateKey"`
+	PositionSize      float64 `json:"positionSize"` // SOL per buy
+	MaxSlippage       float64 `json:"maxSlippage"`  // Share of a buy's quoted tokens, or a sell's quoted proceeds, it may fall short by
+	MaxTokensToTrack  int     `json:"maxTokensToTrack"`
+	MaxMarketAgeHours int     `json:"maxMarketAgeHours"`
+}
+
+type NotificationsConfig struct {
+	// DedupWindowSeconds drops repeats of a notification within it; 0 keeps them all
+	DedupWindowSeconds int             `json:"dedupWindowSeconds"`
+	DigestSeconds      int             `json:"digestSeconds"` // How often digest channels are sent their batch
+	Templates          TemplatesConfig `json:"templates"`     // Shared by every channel
+	Telegram           TelegramConfig  `json:"telegram"`
+	Discord            WebhookConfig   `json:"discord"`
+	Slack              WebhookConfig   `json:"slack"`
+}
+
+// TemplatesConfig are text/template message templates by notification. An
+// empty one keeps the channel's built-in message; `grind preview` renders
+// them against a sample pair.
+type TemplatesConfig struct {
+	NewPair      string `json:"newPair"`
+	SafetyReport string `json:"safetyReport"`
+	Alert        string `json:"alert"`
+	Trade        string `json:"trade"`
+	Digest       string `json:"digest"`
+}
+
+func (t TemplatesConfig) sources() map[string]string {
+	return map[string]string{
+		notifications.TEMPLATE_NEW_PAIR:      t.NewPair,
+		notifications.TEMPLATE_SAFETY_REPORT: t.SafetyReport,
+		notifications.TEMPLATE_ALERT:         t.Alert,
+		notifications.TEMPLATE_TRADE:         t.Trade,
+		notifications.TEMPLATE_DIGEST:        t.Digest,
+	}
+}
+
+// ChannelTemplates are a channel's own templates over the shared ones, by
+// template name.
+func (n NotificationsConfig) ChannelTemplates(channel ChannelConfig) map[string]string {
+	sources := n.Templates.sources()
+	for name, source := range channel.Templates.sources() {
+		if source != "" {
+			sources[name] = source
+		}
+	}
+	return sources
+}
+
+// ChannelConfig is what every notification channel is sent and when. Trades and
+// alerts from warning up always go out at once.
+type ChannelConfig struct {
+	MinSeverity  types.AlertSeverity `json:"minSeverity"`  // info, warning or critical
+	MaxPerMinute int                 `json:"maxPerMinute"` // Cap on other notifications; 0 is no cap
+	Digest       bool                `json:"digest"`       // Batch other notifications every digestSeconds
+	Templates    TemplatesConfig     `json:"templates"`    // Override the shared templates
+}
+
+type TelegramConfig struct {
+	BotToken string `json:"botToken"`
+	ChatID   string `json:"chatId"`
+	ChannelConfig
+	Bot TelegramBotConfig `json:"bot"`
+}
+
+// TelegramBotConfig lets whitelisted chats control the scanner through the
+// bot: status, checks, trades and pausing.
+type TelegramBotConfig struct {
+	Enabled        bool      `json:"enabled"`
+	AllowedChatIDs []int64   `json:"allowedChatIds"`
+	BuyPresets     []float64 `json:"buyPresets"` // SOL amounts offered as buttons on passing tokens
+	MaxBuy         float64   `json:"maxBuy"`     // Most SOL one buy from the bot may spend
+}
+
+// WebhookConfig is an incoming-webhook channel, enabled by setting its URL.
+type WebhookConfig struct {
+	WebhookURL string `json:"webhookUrl"`
+	ChannelConfig
+}
+
+type StorageConfig struct {
+	DatabasePath string `json:"databasePath"`
+	TrackerPath  string `json:"trackerPath"`
+	// PositionsPath is shared by the scanner and the buy, sell and positions commands
+	PositionsPath string `json:"positionsPath"`
+	// PersistCache keeps safety and metrics lookups in the database across restarts
+	PersistCache bool `json:"persistCache"`
+	// CacheSize caps the cached lookups, one per token and check
+	CacheSize int `json:"cacheSize"`
+	// CacheTTLSeconds is how long each kind of lookup stays fresh; 0 turns its caching off
+	CacheTTLSeconds map[string]int `json:"cacheTtlSeconds"`
+}
+
+// MetricsConfig serves Prometheus metrics at /metrics when enabled.
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled"`
+	Listen  string `json:"listen"` // host:port
+}
+
+// APIConfig serves the local HTTP/JSON API when enabled. It has no
+// authentication, so keep it on a loopback address.
+type APIConfig struct {
+	Enabled bool   `json:"enabled"`
+	Listen  string `json:"listen"` // host:port
+}
+
+// secretEnv lists the settings that can come from the environment instead of
+// the config file, and win over it when set.
+var secretEnv = []struct {
+	name  string
+	path  string // As reported by Diff
+	field func(c *Config) *string
+}{
+	{"GRIND_TELEGRAM_BOT_TOKEN", "notifications.telegram.botToken", func(c *Config) *string { return &c.Notifications.Telegram.BotToken }},
+	{"GRIND_TELEGRAM_CHAT_ID", "notifications.telegram.chatId", func(c *Config) *string { return &c.Notifications.Telegram.ChatID }},
+	{"GRIND_DISCORD_WEBHOOK_URL", "notifications.discord.webhookUrl", func(c *Config) *string { return &c.Notifications.Discord.WebhookURL }},
+	{"GRIND_SLACK_WEBHOOK_URL", "notifications.slack.webhookUrl", func(c *Config) *string { return &c.Notifications.Slack.WebhookURL }},
+	{"GRIND_TWITTER_BEARER_TOKEN", "sources.twitterBearerToken", func(c *Config) *string { return &c.Sources.TwitterBearerToken }},
+	{"GRIND_RPC_URL", "rpc.url", func(c *Config) *string { return &c.RPC.URL }},
+	{"GRIND_RPC_WS_URL", "rpc.websocketUrl", func(c *Config) *string { return &c.RPC.WebsocketURL }},
+	{"GRIND_WALLET_ADDRESS", "trading.walletAddress", func(c *Config) *string { return &c.Trading.WalletAddress }},
+	{"GRIND_WALLET_PRIVATE_KEY", "trading.privateKey", func(c *Config) *string { return &c.Trading.PrivateKey }},
+}
+
+// Default is the configuration used for anything the config file leaves out.
+func Default() Config {
+	return Config{
+		Sources: SourcesConfig{
+			RaydiumPairsURL:       "https://api.raydium.io/v2/main/pairs",
+			RaydiumPoolURL:        "https://api.raydium.io/v2/main/pool",
+			SolscanURL:            "https://public-api.solscan.io",
+			GoPlusURL:             "https://api.gopluslabs.io/api/v1/token_security/solana",
+			FetchIntervalSeconds:  types.FETCH_INTERVAL_SECONDS,
+			RequestTimeoutSeconds: 10,
+			PriceSource:           types.PRICE_SOURCE_PAIRS,
+			PriceSampleSeconds:    types.DEFAULT_PRICE_SAMPLE_SECONDS,
+			GoPlusBatchSize:       20,
+		},
+		RPC: RPCConfig{
+			URL:          rpc.MainNetBeta_RPC,
+			WebsocketURL: rpc.MainNetBeta_WS,
+		},
+		Safety: SafetyConfig{
+			MinLiquidity:    10000,
+			MaxMarketCap:    types.MAX_MARKET_CAP_USD,
+			MinHolders:      types.MIN_HOLDER_COUNT,
+			MaxTopHolder:    0.15,
+			MinLockTime:     30 * 24 * 60 * 60,
+			MaxTax:          20,
+			FailurePolicies: map[string]types.CheckPolicy{},
+			RugWatch:        types.DefaultRugWatchConfig(),
+		},
+		Scoring: analytics.ScoringConfig{
+			Primary: "heuristic",
+			Models:  []analytics.ScoringModel{{Name: "heuristic", Type: "heuristic"}},
+		},
+		Trading: TradingConfig{
+			PositionSize:      0.1,
+			MaxSlippage:       0.05,
+			MaxTokensToTrack:  types.MAX_TOKENS_TO_TRACK,
+			MaxMarketAgeHours: int(types.MAX_MARKET_AGE / time.Hour),
+		},
+		Notifications: NotificationsConfig{
+			DedupWindowSeconds: 600,
+			DigestSeconds:      300,
+			Telegram: TelegramConfig{
+				ChannelConfig: ChannelConfig{MinSeverity: types.SeverityInfo},
+				Bot:           TelegramBotConfig{BuyPresets: []float64{0.1, 0.25, 0.5}, MaxBuy: 0.5},
+			},
+			Discord: WebhookConfig{ChannelConfig: ChannelConfig{MinSeverity: types.SeverityInfo}},
+			Slack:   WebhookConfig{ChannelConfig: ChannelConfig{MinSeverity: types.SeverityInfo}},
+		},
+		Storage: StorageConfig{
+			DatabasePath:  "grind.db",
+			TrackerPath:   "tracked_tokens.json",
+			PositionsPath: "positions.json",
+			PersistCache:  true,
+			CacheSize:     5000,
+			CacheTTLSeconds: map[string]int{
+				"metrics":        60,
+				"liquidity_lock": 30 * 60,
+				"honeypot":       60 * 60,
+				"holders":        2 * 60,
+				"social":         6 * 60 * 60,
+				"metadata":       60 * 60,
+			},
+		},
+		Logging: logging.DefaultConfig(),
+		Metrics: MetricsConfig{Listen: "127.0.0.1:9464"},
+		API:     APIConfig{Listen: "127.0.0.1:8080"},
+	}
+}
+
+// LoadConfig reads each file over the defaults in turn, so later files
+// override earlier ones, then applies environment overrides. Unknown keys are
+// an error so typos do not go unnoticed.
+func LoadConfig(paths ...string) (*Config, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config file given")
+	}
+
+	merged := make(map[string]any)
+	for _, path := range paths {
+		layer, err := readLayer(path)
+		if err != nil {
+			return nil, err
+		}
+		mergeLayer(merged, layer)
+	}
+
+	data, err := json.Marshal(merged)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to merge %s: %w", strings.Join(paths, ", "), err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(file, &config); err != nil {
-		return nil, err
+	config := Default()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid config in %s: %w", strings.Join(paths, ", "), err)
 	}
 
+	config.applyEnv()
 	return &config, nil
 }
+
+func (c *Config) applyEnv() {
+	for _, env := range secretEnv {
+		if value, ok := os.LookupEnv(env.name); ok && value != "" {
+			*env.field(c) = value
+		}
+	}
+}
+
+// Validate reports every problem with the configuration at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	for _, endpoint := range []struct{ name, value string }{
+		{"sources.raydiumPairsUrl", c.Sources.RaydiumPairsURL},
+		{"sources.raydiumPoolUrl", c.Sources.RaydiumPoolURL},
+		{"sources.solscanUrl", c.Sources.SolscanURL},
+		{"sources.goPlusUrl", c.Sources.GoPlusURL},
+		{"rpc.url", c.RPC.URL},
+	} {
+		check(validURL(endpoint.value, "http", "https"), "%s: %q is not an http(s) URL", endpoint.name, endpoint.value)
+	}
+	check(validURL(c.RPC.WebsocketURL, "ws", "wss"), "rpc.websocketUrl: %q is not a ws(s) URL", c.RPC.WebsocketURL)
+	check(c.Sources.FetchIntervalSeconds > 0, "sources.fetchIntervalSeconds: must be positive")
+	check(c.Sources.RequestTimeoutSeconds > 0, "sources.requestTimeoutSeconds: must be positive")
+	check(c.Sources.PriceSampleSeconds > 0, "sources.priceSampleSeconds: must be positive")
+	check(c.Sources.GoPlusBatchSize > 0, "sources.goPlusBatchSize: must be positive")
+	check(c.Sources.PriceSource == types.PRICE_SOURCE_PAIRS || c.Sources.PriceSource == types.PRICE_SOURCE_RESERVES,
+		"sources.priceSource: %q is not %s or %s", c.Sources.PriceSource, types.PRICE_SOURCE_PAIRS, types.PRICE_SOURCE_RESERVES)
+
+	check(c.Safety.MinLiquidity >= 0, "safety.minLiquidity: must not be negative")
+	check(c.Safety.MaxMarketCap > 0, "safety.maxMarketCap: must be positive")
+	check(c.Safety.MinHolders >= 0, "safety.minHolders: must not be negative")
+	check(c.Safety.MaxTopHolder > 0 && c.Safety.MaxTopHolder <= 1, "safety.maxTopHolder: must be a share in (0, 1]")
+	check(c.Safety.MinLockTime >= 0, "safety.minLockTime: must not be negative")
+	check(c.Safety.MinAge >= 0, "safety.minAge: must not be negative")
+	check(c.Safety.MaxTax >= 0 && c.Safety.MaxTax <= 100, "safety.maxTax: must be a percentage in [0, 100]")
+	for i, rule := range c.Safety.Rules {
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("safety.rules[%d]: %w", i, err))
+		}
+	}
+	for name, policy := range c.Safety.FailurePolicies {
+		check(types.IsSourcedCheck(name), "safety.failurePolicies.%s: not a check, expected one of %s",
+			name, strings.Join(types.SOURCED_CHECKS, ", "))
+		check(policy == types.FailClosed || policy == types.FailOpen,
+			"safety.failurePolicies.%s: %q is not %s or %s", name, policy, types.FailClosed, types.FailOpen)
+	}
+	rug := c.Safety.RugWatch
+	check(rug.LiquidityDrop > 0 && rug.LiquidityDrop <= 1, "safety.rugWatch.liquidityDrop: must be a share in (0, 1]")
+	check(rug.SellShare > 0 && rug.SellShare <= 1, "safety.rugWatch.sellShare: must be a share in (0, 1]")
+	check(rug.SupplyIncrease > 0, "safety.rugWatch.supplyIncrease: must be positive")
+	check(rug.TopHolders > 0, "safety.rugWatch.topHolders: must be positive")
+	check(rug.CheckSeconds > 0, "safety.rugWatch.checkSeconds: must be positive")
+
+	if _, err := analytics.NewScorers(c.Scoring, c.AnalyzerConfig()); err != nil {
+		errs = append(errs, fmt.Errorf("scoring: %w", err))
+	}
+
+	if c.Trading.WalletAddress == "" {
+		errs = append(errs, fmt.Errorf("trading.walletAddress: required (or set GRIND_WALLET_ADDRESS)"))
+	} else if _, err := solana.PublicKeyFromBase58(c.Trading.WalletAddress); err != nil {
+		errs = append(errs, fmt.Errorf("trading.walletAddress: %w", err))
+	} else if c.Trading.PrivateKey != "" {
+		if key, err := solana.PrivateKeyFromBase58(c.Trading.PrivateKey); err != nil || len(key) != ed25519.PrivateKeySize {
+			errs = append(errs, fmt.Errorf("trading.privateKey: not a base58 secret key"))
+		} else {
+			check(key.PublicKey().String() == c.Trading.WalletAddress, "trading.privateKey: is not the key of trading.walletAddress")
+		}
+	}
+	check(!rug.EmergencySell || c.Trading.PrivateKey != "",
+		"safety.rugWatch.emergencySell: needs trading.privateKey (or GRIND_WALLET_PRIVATE_KEY) to sign the sell")
+	check(c.Trading.PositionSize > 0, "trading.positionSize: must be positive")
+	check(c.Trading.MaxSlippage > 0 && c.Trading.MaxSlippage < 1, "trading.maxSlippage: must be a share in (0, 1)")
+	check(c.Trading.MaxTokensToTrack > 0, "trading.maxTokensToTrack: must be positive")
+	check(c.Trading.MaxMarketAgeHours > 0, "trading.maxMarketAgeHours: must be positive")
+
+	check(c.Notifications.DedupWindowSeconds >= 0, "notifications.dedupWindowSeconds: must not be negative")
+	check(c.Notifications.DigestSeconds > 0, "notifications.digestSeconds: must be positive")
+	if _, err := notifications.ParseTemplates(c.Notifications.Templates.sources()); err != nil {
+		errs = append(errs, fmt.Errorf("notifications.templates: %w", err))
+	}
+	telegram := c.Notifications.Telegram
+	check((telegram.BotToken == "") == (telegram.ChatID == ""),
+		"notifications.telegram: botToken and chatId must be set together")
+	if telegram.Bot.Enabled {
+		check(telegram.BotToken != "", "notifications.telegram.bot: needs botToken")
+		check(len(telegram.Bot.AllowedChatIDs) > 0,
+			"notifications.telegram.bot.allowedChatIds: required, or any chat could trade")
+	}
+	check(telegram.Bot.MaxBuy > 0, "notifications.telegram.bot.maxBuy: must be positive")
+	for _, preset := range telegram.Bot.BuyPresets {
+		check(preset > 0, "notifications.telegram.bot.buyPresets: %g is not a positive amount of SOL", preset)
+		check(preset <= telegram.Bot.MaxBuy, "notifications.telegram.bot.buyPresets: %g is over maxBuy", preset)
+	}
+	for _, webhook := range []struct {
+		name string
+		WebhookConfig
+	}{
+		{"discord", c.Notifications.Discord},
+		{"slack", c.Notifications.Slack},
+	} {
+		check(webhook.WebhookURL == "" || validURL(webhook.WebhookURL, "https"),
+			"notifications.%s.webhookUrl: not an https URL", webhook.name)
+	}
+	for _, channel := range []struct {
+		name string
+		ChannelConfig
+	}{
+		{"telegram", telegram.ChannelConfig},
+		{"discord", c.Notifications.Discord.ChannelConfig},
+		{"slack", c.Notifications.Slack.ChannelConfig},
+	} {
+		check(channel.MinSeverity.Valid(), "notifications.%s.minSeverity: must be info, warning or critical", channel.name)
+		check(channel.MaxPerMinute >= 0, "notifications.%s.maxPerMinute: must not be negative", channel.name)
+		if _, err := notifications.ParseTemplates(channel.Templates.sources()); err != nil {
+			errs = append(errs, fmt.Errorf("notifications.%s.templates: %w", channel.name, err))
+		}
+	}
+
+	check(c.Storage.DatabasePath != "", "storage.databasePath: required")
+	check(c.Storage.TrackerPath != "", "storage.trackerPath: required")
+	check(c.Storage.PositionsPath != "", "storage.positionsPath: required")
+	check(c.Storage.CacheSize > 0, "storage.cacheSize: must be positive")
+	cached := Default().Storage.CacheTTLSeconds
+	for lookup, seconds := range c.Storage.CacheTTLSeconds {
+		_, known := cached[lookup]
+		check(known, "storage.cacheTtlSeconds.%s: not a cached lookup", lookup)
+		check(seconds >= 0, "storage.cacheTtlSeconds.%s: must not be negative", lookup)
+	}
+
+	if err := c.Logging.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("logging: %w", err))
+	}
+	if c.Metrics.Enabled {
+		if _, _, err := net.SplitHostPort(c.Metrics.Listen); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.listen: %w", err))
+		}
+	}
+	if c.API.Enabled {
+		if _, _, err := net.SplitHostPort(c.API.Listen); err != nil {
+			errs = append(errs, fmt.Errorf("api.listen: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validURL(value string, schemes ...string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzerConfig is the analytics.TokenAnalyzer configuration for these settings.
+func (c *Config) AnalyzerConfig() analytics.TokenAnalyzerConfig {
+	return analytics.TokenAnalyzerConfig{
+		MinLiquidity:   c.Safety.MinLiquidity,
+		MaxMarketCap:   c.Safety.MaxMarketCap,
+		MinHolderCount: c.Safety.MinHolders,
+		MaxTopHolder:   c.Safety.MaxTopHolder,
+		MinAge:         c.Safety.MinAge,
+		MinLockTime:    c.Safety.MinLockTime,
+		Rules:          c.Safety.Rules,
+	}
+}
+
+func (c *Config) PriceSampleInterval() time.Duration {
+	return time.Duration(c.Sources.PriceSampleSeconds) * time.Second
+}
+
+func (c *Config) MaxMarketAge() time.Duration {
+	return time.Duration(c.Trading.MaxMarketAgeHours) * time.Hour
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const sampleWallet = "79hjkpSwnJ4g7PJ7YYQfJRGEwHwWWUB7ziyve15fC4YC"
+
+func TestLoadConfigDefaults(t *testing.T) {
+	config, err := LoadConfig("testdata/minimal.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("defaults with a wallet do not validate: %v", err)
+	}
+
+	want := Default()
+	want.Trading.WalletAddress = sampleWallet
+	if !reflect.DeepEqual(*config, want) {
+		t.Errorf("a file with only the wallet changed other settings:\ngot  %+v\nwant %+v", *config, want)
+	}
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+	config, err := LoadConfig("testdata/minimal.json", "testdata/overrides.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := Default()
+	if config.Sources.PriceSource != types.PRICE_SOURCE_RESERVES || config.Safety.MinLiquidity != 2500 {
+		t.Errorf("overrides not applied: %+v, %+v", config.Sources, config.Safety)
+	}
+	if config.Safety.RugWatch.LiquidityDrop != 0.3 || config.Safety.RugWatch.TopHolders != defaults.Safety.RugWatch.TopHolders {
+		t.Errorf("rugWatch = %+v, want liquidityDrop 0.3 over the defaults", config.Safety.RugWatch)
+	}
+	if config.Notifications.Telegram.MinSeverity != types.SeverityWarning || config.Notifications.Discord.MinSeverity != types.SeverityInfo {
+		t.Errorf("severities = %s, %s", config.Notifications.Telegram.MinSeverity, config.Notifications.Discord.MinSeverity)
+	}
+	if config.Trading.WalletAddress != sampleWallet {
+		t.Errorf("the later file dropped the earlier file's wallet")
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	for _, test := range []struct {
+		paths []string
+		error string
+	}{
+		{nil, "no config file given"},
+		{[]string{"testdata/unknown_key.json"}, `unknown field "minLiquidty"`},
+		{[]string{"testdata/malformed.json"}, "failed to parse testdata/malformed.json"},
+		{[]string{"testdata/minimal.json", "testdata/missing.json"}, "missing.json"},
+	} {
+		_, err := LoadConfig(test.paths...)
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("%v: got %v, want an error with %q", test.paths, err, test.error)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("GRIND_TELEGRAM_BOT_TOKEN", "123:abc")
+	t.Setenv("GRIND_RPC_URL", "https://rpc.example.com")
+	t.Setenv("GRIND_WALLET_ADDRESS", "")
+
+	config, err := LoadConfig("testdata/minimal.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Notifications.Telegram.BotToken != "123:abc" || config.RPC.URL != "https://rpc.example.com" {
+		t.Errorf("environment not applied: bot token %q, rpc %q", config.Notifications.Telegram.BotToken, config.RPC.URL)
+	}
+	if config.Trading.WalletAddress != sampleWallet {
+		t.Errorf("an empty variable replaced the wallet with %q", config.Trading.WalletAddress)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	config, err := LoadConfig("testdata/invalid.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.Validate()
+	if err == nil {
+		t.Fatal("an invalid config validated")
+	}
+	// Every problem is reported at once
+	for _, want := range []string{
+		"sources.raydiumPairsUrl",
+		`sources.priceSource: "oracle"`,
+		"rpc.websocketUrl",
+		"sources.goPlusBatchSize",
+		"safety.maxTopHolder",
+		"safety.maxTax",
+		"safety.failurePolicies.honeypot",
+		"safety.failurePolicies.mint_authorty: not a check",
+		"safety.rugWatch.emergencySell: needs trading.privateKey",
+		"trading.walletAddress",
+		"trading.positionSize",
+		"notifications.telegram: botToken and chatId",
+		"notifications.telegram.bot.allowedChatIds",
+		"notifications.discord.webhookUrl",
+		"storage.cacheSize",
+		"storage.cacheTtlSeconds.holder: not a cached lookup",
+		"storage.cacheTtlSeconds.social: must not be negative",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("no %s error in:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidatePrivateKey(t *testing.T) {
+	key, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name          string
+		key           string
+		emergencySell bool
+		error         string
+	}{
+		{"no key", "", false, ""},
+		{"matching key", key.String(), true, ""},
+		{"emergency sell without a key", "", true, "safety.rugWatch.emergencySell"},
+		{"another wallet's key", other.String(), false, "is not the key of trading.walletAddress"},
+		{"not base58", "0OIl", false, "not a base58 secret key"},
+		{"too short", key.PublicKey().String(), false, "not a base58 secret key"},
+	} {
+		config := Default()
+		config.Trading.WalletAddress = key.PublicKey().String()
+		config.Trading.PrivateKey = test.key
+		config.Safety.RugWatch.EmergencySell = test.emergencySell
+
+		err := config.Validate()
+		if test.error == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("%s: got %v, want an error with %q", test.name, err, test.error)
+		}
+		if err != nil && strings.Contains(err.Error(), test.key) && test.key != "" {
+			t.Errorf("%s: the error repeats the key", test.name)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Change is one setting that differs between two configurations.
+type Change struct {
+	Path string
+	Old  string
+	New  string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Diff lists every setting that differs between old and new, by JSON path.
+// Secret values are masked.
+func Diff(old, new *Config) ([]Change, error) {
+	oldValues, err := flatten(old)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := flatten(new)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]bool, len(secretEnv))
+	for _, env := range secretEnv {
+		secrets[env.path] = true
+	}
+
+	paths := make(map[string]bool)
+	for path := range oldValues {
+		paths[path] = true
+	}
+	for path := range newValues {
+		paths[path] = true
+	}
+
+	changes := make([]Change, 0)
+	for path := range paths {
+		oldValue, hadOld := oldValues[path]
+		newValue, hasNew := newValues[path]
+		if hadOld && hasNew && oldValue == newValue {
+			continue
+		}
+		if !hadOld {
+			oldValue = "(unset)"
+		}
+		if !hasNew {
+			newValue = "(unset)"
+		}
+		if secrets[path] {
+			oldValue, newValue = "***", "***"
+		}
+		changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// flatten maps each leaf of the config's JSON form to its path, e.g.
+// "scoring.models[1].terms[0].weight".
+func flatten(config *Config) (map[string]string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	values := make(map[string]string)
+	var walk func(path string, node any)
+	walk = func(path string, node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			for key, child := range node {
+				if path == "" {
+					walk(key, child)
+				} else {
+					walk(path+"."+key, child)
+				}
+			}
+		case []any:
+			for i, child := range node {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		default:
+			encoded, _ := json.Marshal(node)
+			values[path] = string(encoded)
+		}
+	}
+	walk("", tree)
+	return values, nil
+}
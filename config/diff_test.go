@@ -0,0 +1,85 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"grind/analytics"
+)
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	new := Default()
+	new.Safety.MinLiquidity = 2500
+	new.Trading.WalletAddress = sampleWallet
+	new.Scoring.Models = append(new.Scoring.Models, analytics.ScoringModel{Name: "linear-v1", Type: analytics.ModelLinear})
+	new.Scoring.Models[0].Terms = []analytics.LinearTerm{{Field: analytics.FieldLiquidity, Weight: 0.5}}
+
+	changes, err := Diff(&old, &new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	// Paths are sorted; entries that appear or disappear are (unset) on one side
+	want := []string{
+		`safety.minLiquidity: 10000 -> 2500`,
+		`scoring.models[0].terms: null -> (unset)`,
+		`scoring.models[0].terms[0].cap: (unset) -> 0`,
+		`scoring.models[0].terms[0].field: (unset) -> "liquidity"`,
+		`scoring.models[0].terms[0].weight: (unset) -> 0.5`,
+		`scoring.models[1].bias: (unset) -> 0`,
+		`scoring.models[1].name: (unset) -> "linear-v1"`,
+		`scoring.models[1].terms: (unset) -> null`,
+		`scoring.models[1].type: (unset) -> "linear"`,
+		`trading.walletAddress: *** -> ***`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes:\ngot  %s\nwant %s", strings.Join(got, "\n     "), strings.Join(want, "\n     "))
+	}
+
+	if changes, err := Diff(&old, &old); err != nil || len(changes) != 0 {
+		t.Errorf("a config against itself gave %v, %v", changes, err)
+	}
+}
+
+func TestDiffMasksSecrets(t *testing.T) {
+	old := Default()
+	new := Default()
+	for _, env := range secretEnv {
+		*env.field(&new) = "secret-" + env.name
+	}
+
+	changes, err := Diff(&old, &new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != len(secretEnv) {
+		t.Errorf("got %d changes for %d secrets: %v", len(changes), len(secretEnv), changes)
+	}
+	for _, change := range changes {
+		if change.Old != "***" || change.New != "***" || strings.Contains(change.String(), "secret-") {
+			t.Errorf("%s is not masked", change)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	config := Default()
+	config.Trading.WalletAddress = sampleWallet
+	config.Notifications.Telegram.BotToken = "123:abc"
+
+	redacted := config.Redacted()
+	if redacted.Trading.WalletAddress != "***" || redacted.Notifications.Telegram.BotToken != "***" {
+		t.Errorf("secrets left in the redacted copy: %+v, %+v", redacted.Trading, redacted.Notifications.Telegram)
+	}
+	if redacted.Notifications.Discord.WebhookURL != "" {
+		t.Errorf("an unset secret was masked")
+	}
+	if config.Trading.WalletAddress != sampleWallet {
+		t.Errorf("redacting changed the original")
+	}
+}
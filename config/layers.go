@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// CONFIG_EXTENSIONS are the supported config formats, in the order a profile
+// overlay is looked for.
+var CONFIG_EXTENSIONS = []string{".json", ".yaml", ".yml", ".toml"}
+
+// readLayer decodes a config file into a generic tree, choosing the format by
+// extension. Keys are the same in every format.
+func readLayer(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	layer := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&layer)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &layer)
+	case ".toml":
+		err = toml.Unmarshal(data, &layer)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return layer, nil
+}
+
+// mergeLayer lays overlay over base: tables merge key by key, anything else
+// (values and lists alike) is replaced.
+func mergeLayer(base, overlay map[string]any) {
+	for key, value := range overlay {
+		overlayTable, isTable := value.(map[string]any)
+		baseTable, baseIsTable := base[key].(map[string]any)
+		if isTable && baseIsTable {
+			mergeLayer(baseTable, overlayTable)
+			continue
+		}
+		base[key] = value
+	}
+}
+
+// ProfilePaths is the base config followed by the overlay for profile, e.g.
+// config.yaml and config.paper.yaml. The overlay may use any supported format.
+func ProfilePaths(base, profile string) ([]string, error) {
+	if profile == "" {
+		return []string{base}, nil
+	}
+
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	for _, ext := range CONFIG_EXTENSIONS {
+		overlay := stem + "." + profile + ext
+		if _, err := os.Stat(overlay); err == nil {
+			return []string{base, overlay}, nil
+		}
+	}
+	return nil, fmt.Errorf("no config overlay for profile %q next to %s", profile, base)
+}
+
+// Redacted is a copy of the config with every secret that is set masked, safe
+// to print or log.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, env := range secretEnv {
+		if value := env.field(&redacted); *value != "" {
+			*value = "***"
+		}
+	}
+	return &redacted
+}
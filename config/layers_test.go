@@ -0,0 +1,98 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeLayer(t *testing.T) {
+	base := map[string]any{
+		"safety":  map[string]any{"minLiquidity": 5000, "rugWatch": map[string]any{"liquidityDrop": 0.4, "topHolders": 8}},
+		"presets": []any{0.1, 0.25},
+		"wallet":  "base",
+	}
+	mergeLayer(base, map[string]any{
+		"safety":  map[string]any{"rugWatch": map[string]any{"liquidityDrop": 0.6}},
+		"presets": []any{0.01},
+		"api":     map[string]any{"enabled": true},
+	})
+
+	want := map[string]any{
+		"safety":  map[string]any{"minLiquidity": 5000, "rugWatch": map[string]any{"liquidityDrop": 0.6, "topHolders": 8}},
+		"presets": []any{0.01},
+		"wallet":  "base",
+		"api":     map[string]any{"enabled": true},
+	}
+	if !reflect.DeepEqual(base, want) {
+		t.Errorf("got  %v\nwant %v", base, want)
+	}
+}
+
+func TestProfilePaths(t *testing.T) {
+	for _, test := range []struct {
+		profile string
+		want    []string
+		error   string
+	}{
+		{"", []string{"testdata/profiles/config.json"}, ""},
+		{"paper", []string{"testdata/profiles/config.json", "testdata/profiles/config.paper.yaml"}, ""},
+		{"live", []string{"testdata/profiles/config.json", "testdata/profiles/config.live.toml"}, ""},
+		{"staging", nil, `no config overlay for profile "staging"`},
+	} {
+		paths, err := ProfilePaths("testdata/profiles/config.json", test.profile)
+		if test.error != "" {
+			if err == nil || !strings.Contains(err.Error(), test.error) {
+				t.Errorf("%q: got %v, want an error with %q", test.profile, err, test.error)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(paths, test.want) {
+			t.Errorf("%q: got %v, %v; want %v", test.profile, paths, err, test.want)
+		}
+	}
+}
+
+func TestLoadConfigProfiles(t *testing.T) {
+	for _, test := range []struct {
+		profile       string
+		minLiquidity  float64
+		positionSize  float64
+		liquidityDrop float64
+		topHolders    int
+		presets       []float64
+	}{
+		{"", 5000, 0.2, 0.4, 8, []float64{0.1, 0.25, 0.5}},
+		{"paper", 1000, 0.01, 0.6, 8, []float64{0.01}},
+		{"live", 20000, 0.2, 0.4, 10, []float64{0.5, 1}},
+	} {
+		paths, err := ProfilePaths("testdata/profiles/config.json", test.profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := LoadConfig(paths...)
+		if err != nil {
+			t.Fatalf("%q: %v", test.profile, err)
+		}
+
+		rug := config.Safety.RugWatch
+		if config.Safety.MinLiquidity != test.minLiquidity || config.Trading.PositionSize != test.positionSize ||
+			rug.LiquidityDrop != test.liquidityDrop || rug.TopHolders != test.topHolders {
+			t.Errorf("%q: minLiquidity %g, positionSize %g, rugWatch %+v", test.profile,
+				config.Safety.MinLiquidity, config.Trading.PositionSize, rug)
+		}
+		// Lists are replaced, not merged
+		if presets := config.Notifications.Telegram.Bot.BuyPresets; !reflect.DeepEqual(presets, test.presets) {
+			t.Errorf("%q: buyPresets = %v, want %v", test.profile, presets, test.presets)
+		}
+		if config.Trading.WalletAddress != sampleWallet || rug.SupplyIncrease != Default().Safety.RugWatch.SupplyIncrease {
+			t.Errorf("%q: lost settings the overlay does not mention", test.profile)
+		}
+	}
+}
+
+func TestReadLayerUnsupportedFormat(t *testing.T) {
+	if _, err := readLayer("layers_test.go"); err == nil || !strings.Contains(err.Error(), `unsupported config format ".go"`) {
+		t.Errorf("got %v, want an unsupported format error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchFile signals changed whenever the file's size or modification time
+// changes. Polling, rather than file system events, also catches editors that
+// replace the file instead of writing to it.
+func WatchFile(ctx context.Context, path string, interval time.Duration, changed chan<- struct{}) {
+	last, _ := os.Stat(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue // Mid-replace; try again next tick
+			}
+			if last != nil && info.Size() == last.Size() && info.ModTime().Equal(last.ModTime()) {
+				continue
+			}
+			last = info
+
+			select {
+			case changed <- struct{}{}:
+			default: // A reload is already pending
+			}
+		}
+	}
+}
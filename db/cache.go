@@ -0,0 +1,59 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"grind/types"
+)
+
+// LoadCacheEntries returns every unexpired cache entry, purging expired ones.
+func (d *SQLiteDB) LoadCacheEntries() ([]types.CacheEntry, error) {
+	if _, err := d.PurgeCacheEntries(time.Now()); err != nil {
+		return nil, err
+	}
+
+	rows, err := d.conn.Query(`SELECT mint, check_name, value, expires_at FROM cache_entries`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]types.CacheEntry, 0)
+	for rows.Next() {
+		var (
+			entry     types.CacheEntry
+			value     string
+			expiresAt int64
+		)
+		if err := rows.Scan(&entry.Mint, &entry.Check, &value, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cache entry: %w", err)
+		}
+		entry.Value = []byte(value)
+		entry.ExpiresAt = time.Unix(expiresAt, 0)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (d *SQLiteDB) SaveCacheEntry(entry types.CacheEntry) error {
+	_, err := d.conn.Exec(
+		`INSERT INTO cache_entries (mint, check_name, value, expires_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (mint, check_name) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`,
+		entry.Mint, entry.Check, string(entry.Value), entry.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save cache entry: %w", err)
+	}
+	return nil
+}
+
+// PurgeCacheEntries deletes the entries expired by before, returning how many.
+func (d *SQLiteDB) PurgeCacheEntries(before time.Time) (int64, error) {
+	result, err := d.conn.Exec(`DELETE FROM cache_entries WHERE expires_at <= ?`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge cache entries: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"grind/types"
+)
+
+// StoreCandle writes a candle, merging it into any stored version of the same
+// bar: the stored open is kept and the range only ever widens. Volume and
+// samples are running totals the aggregator resumes from the stored bar, so
+// the larger total is kept rather than summed.
+func (d *SQLiteDB) StoreCandle(candle types.Candle) error {
+	_, err := d.conn.Exec(
+		`INSERT INTO candles (mint, interval, open_time, open, high, low, close, volume, liquidity, samples)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (mint, interval, open_time) DO UPDATE SET
+			high = max(high, excluded.high), low = min(low, excluded.low), close = excluded.close,
+			volume = max(volume, excluded.volume), liquidity = excluded.liquidity,
+			samples = max(samples, excluded.samples)`,
+		candle.Mint, candle.Interval, candle.OpenTime.Unix(),
+		candle.Open, candle.High, candle.Low, candle.Close,
+		candle.Volume, candle.Liquidity, candle.Samples,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store candle: %w", err)
+	}
+	return nil
+}
+
+// LoadCandle returns the mint's candle of one interval opened at openTime, or
+// nil if there is none.
+func (d *SQLiteDB) LoadCandle(mint, interval string, openTime time.Time) (*types.Candle, error) {
+	candle := types.Candle{Mint: mint, Interval: interval, OpenTime: openTime}
+	err := d.conn.QueryRow(
+		`SELECT open, high, low, close, volume, liquidity, samples
+		 FROM candles WHERE mint = ? AND interval = ? AND open_time = ?`,
+		mint, interval, openTime.Unix(),
+	).Scan(&candle.Open, &candle.High, &candle.Low, &candle.Close, &candle.Volume, &candle.Liquidity, &candle.Samples)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candle: %w", err)
+	}
+	return &candle, nil
+}
+
+// LoadCandles returns the mint's candles of one interval opened at or after since, oldest first.
+func (d *SQLiteDB) LoadCandles(mint, interval string, since time.Time) ([]types.Candle, error) {
+	rows, err := d.conn.Query(
+		`SELECT open_time, open, high, low, close, volume, liquidity, samples
+		 FROM candles WHERE mint = ? AND interval = ? AND open_time >= ? ORDER BY open_time`,
+		mint, interval, since.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+	defer rows.Close()
+
+	candles := make([]types.Candle, 0)
+	for rows.Next() {
+		candle := types.Candle{Mint: mint, Interval: interval}
+		var openTime int64
+		if err := rows.Scan(&openTime, &candle.Open, &candle.High, &candle.Low, &candle.Close,
+			&candle.Volume, &candle.Liquidity, &candle.Samples); err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candle.OpenTime = time.Unix(openTime, 0)
+		candles = append(candles, candle)
+	}
+
+	return candles, rows.Err()
+}
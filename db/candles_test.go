@@ -0,0 +1,77 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+func openTestDatabase(t *testing.T) *SQLiteDB {
+	t.Helper()
+	database, err := NewDatabase(filepath.Join(t.TempDir(), "grind.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestStoreCandleMergesIntoStoredBar(t *testing.T) {
+	database := openTestDatabase(t)
+	openTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	first := types.Candle{Mint: "mint", Interval: "1m", OpenTime: openTime,
+		Open: 1, High: 1.4, Low: 0.9, Close: 1.2, Volume: 300, Liquidity: 5000, Samples: 3}
+	if err := database.StoreCandle(first); err != nil {
+		t.Fatal(err)
+	}
+	// As written by a restarted scanner that could not resume the bar
+	restarted := types.Candle{Mint: "mint", Interval: "1m", OpenTime: openTime,
+		Open: 1.3, High: 1.3, Low: 1.1, Close: 1.1, Volume: 50, Liquidity: 4800, Samples: 1}
+	if err := database.StoreCandle(restarted); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := database.LoadCandle("mint", "1m", openTime)
+	if err != nil || stored == nil {
+		t.Fatalf("got %v, %v", stored, err)
+	}
+	want := types.Candle{Mint: "mint", Interval: "1m", OpenTime: openTime,
+		Open: 1, High: 1.4, Low: 0.9, Close: 1.1, Volume: 300, Liquidity: 4800, Samples: 3}
+	if !stored.OpenTime.Equal(want.OpenTime) {
+		t.Errorf("open time = %v, want %v", stored.OpenTime, want.OpenTime)
+	}
+	stored.OpenTime = want.OpenTime
+	if *stored != want {
+		t.Errorf("got  %+v\nwant %+v", *stored, want)
+	}
+
+	if missing, err := database.LoadCandle("mint", "5m", openTime); missing != nil || err != nil {
+		t.Errorf("got %+v, %v for a bar never stored", missing, err)
+	}
+}
+
+func TestLoadPriceSeries(t *testing.T) {
+	database := openTestDatabase(t)
+	openTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, price := range []float64{1, 1.5, 1.2} {
+		candle := types.Candle{Mint: "mint", Interval: "1m", OpenTime: openTime.Add(time.Duration(i) * time.Minute), Close: price}
+		if err := database.StoreCandle(candle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	database.StoreCandle(types.Candle{Mint: "mint", Interval: "5m", OpenTime: openTime, Close: 9})
+
+	series, err := database.LoadPriceSeries("mint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 3 || series[0].Price != 1 || series[2].Price != 1.2 {
+		t.Fatalf("series = %+v, want the three 1m closes", series)
+	}
+	if !series[0].Time.Equal(openTime.Add(time.Minute)) {
+		t.Errorf("first close at %v, want the end of its bar", series[0].Time)
+	}
+}
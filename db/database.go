@@ -1,18 +1,90 @@
 package db
 
-import "grind/types"
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS cache_entries (
+	mint       TEXT    NOT NULL,
+	check_name TEXT    NOT NULL,
+	value      TEXT    NOT NULL,
+	expires_at INTEGER NOT NULL,
+	PRIMARY KEY (mint, check_name)
+);
+
+CREATE TABLE IF NOT EXISTS safety_reports (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	mint       TEXT    NOT NULL,
+	symbol     TEXT    NOT NULL,
+	passed     INTEGER NOT NULL,
+	data       TEXT    NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_safety_reports_mint ON safety_reports (mint, created_at);
+
+CREATE TABLE IF NOT EXISTS token_scores (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	mint       TEXT    NOT NULL,
+	model      TEXT    NOT NULL,
+	total      REAL    NOT NULL,
+	data       TEXT    NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_token_scores_mint ON token_scores (mint, created_at);
+CREATE INDEX IF NOT EXISTS idx_token_scores_model ON token_scores (model, created_at);
+
+CREATE TABLE IF NOT EXISTS observations (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	mint        TEXT    NOT NULL,
+	data        TEXT    NOT NULL,
+	observed_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_observations_time ON observations (observed_at);
+
+CREATE TABLE IF NOT EXISTS candles (
+	mint      TEXT    NOT NULL,
+	interval  TEXT    NOT NULL,
+	open_time INTEGER NOT NULL,
+	open      REAL    NOT NULL,
+	high      REAL    NOT NULL,
+	low       REAL    NOT NULL,
+	close     REAL    NOT NULL,
+	volume    REAL    NOT NULL,
+	liquidity REAL    NOT NULL,
+	samples   INTEGER NOT NULL,
+	PRIMARY KEY (mint, interval, open_time)
+);
+`
 
 type SQLiteDB struct {
-	StorePair(pair RaydiumPair) error
+	path string
+	conn *sql.DB
 }
 
 func NewDatabase(path string) (*SQLiteDB, error) {
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite only supports a single writer
+	conn.SetMaxOpenConns(1)
+
+	if _, err := conn.Exec(schema); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
 	return &SQLiteDB{
 		path: path,
+		conn: conn,
 	}, nil
 }
 
-func (d *Database) Close() error {
-	// Implement any cleanup needed
-	return nil
+func (d *SQLiteDB) Close() error {
+	return d.conn.Close()
 }
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"grind/types"
+)
+
+func (d *SQLiteDB) StoreObservation(observation types.TokenObservation) error {
+	data, err := json.Marshal(observation)
+	if err != nil {
+		return fmt.Errorf("failed to encode observation: %w", err)
+	}
+
+	_, err = d.conn.Exec(
+		`INSERT INTO observations (mint, data, observed_at) VALUES (?, ?, ?)`,
+		observation.Pair.Address, string(data), observation.ObservedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store observation: %w", err)
+	}
+	return nil
+}
+
+// LoadObservations returns observations made in [since, until), oldest first.
+func (d *SQLiteDB) LoadObservations(since, until time.Time) ([]types.TokenObservation, error) {
+	rows, err := d.conn.Query(
+		`SELECT data FROM observations WHERE observed_at >= ? AND observed_at < ? ORDER BY observed_at`,
+		since.Unix(), until.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load observations: %w", err)
+	}
+	defer rows.Close()
+
+	observations := make([]types.TokenObservation, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan observation: %w", err)
+		}
+
+		var observation types.TokenObservation
+		if err := json.Unmarshal([]byte(data), &observation); err != nil {
+			return nil, fmt.Errorf("failed to decode observation: %w", err)
+		}
+		observations = append(observations, observation)
+	}
+
+	return observations, rows.Err()
+}
+
+// LatestObservation returns the mint's most recent observation, or nil if it has none.
+func (d *SQLiteDB) LatestObservation(mint string) (*types.TokenObservation, error) {
+	var data string
+	err := d.conn.QueryRow(
+		`SELECT data FROM observations WHERE mint = ? ORDER BY observed_at DESC, id DESC LIMIT 1`, mint,
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load observation: %w", err)
+	}
+
+	var observation types.TokenObservation
+	if err := json.Unmarshal([]byte(data), &observation); err != nil {
+		return nil, fmt.Errorf("failed to decode observation: %w", err)
+	}
+	return &observation, nil
+}
+
+// LoadPriceSeries returns the mint's price history, oldest first, as the
+// closes of the 1m candles the price collector recorded.
+func (d *SQLiteDB) LoadPriceSeries(mint string) ([]types.PricePoint, error) {
+	candles, err := d.LoadCandles(mint, "1m", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]types.PricePoint, 0, len(candles))
+	for _, candle := range candles {
+		// A close is only known once its bar has ended
+		series = append(series, types.PricePoint{Time: candle.OpenTime.Add(time.Minute), Price: candle.Close})
+	}
+	return series, nil
+}
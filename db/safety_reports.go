@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"grind/types"
+)
+
+func (d *SQLiteDB) StoreSafetyReport(report types.SafetyReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode safety report: %w", err)
+	}
+
+	_, err = d.conn.Exec(
+		`INSERT INTO safety_reports (mint, symbol, passed, data, created_at) VALUES (?, ?, ?, ?, ?)`,
+		report.Mint, report.Symbol, report.Passed(), string(data), report.GeneratedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store safety report: %w", err)
+	}
+	return nil
+}
+
+// LatestSafetyReport returns the mint's most recent report, or nil if it has none.
+func (d *SQLiteDB) LatestSafetyReport(mint string) (*types.SafetyReport, error) {
+	var data string
+	err := d.conn.QueryRow(
+		`SELECT data FROM safety_reports WHERE mint = ? ORDER BY created_at DESC, id DESC LIMIT 1`, mint,
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load safety report: %w", err)
+	}
+
+	var report types.SafetyReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, fmt.Errorf("failed to decode safety report: %w", err)
+	}
+	return &report, nil
+}
+
+// RecentRejections returns up to limit reports that rejected their token, newest first.
+func (d *SQLiteDB) RecentRejections(limit int) ([]types.SafetyReport, error) {
+	rows, err := d.conn.Query(
+		`SELECT data FROM safety_reports WHERE passed = 0 ORDER BY created_at DESC, id DESC LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rejections: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]types.SafetyReport, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan safety report: %w", err)
+		}
+
+		var report types.SafetyReport
+		if err := json.Unmarshal([]byte(data), &report); err != nil {
+			return nil, fmt.Errorf("failed to decode safety report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"grind/types"
+)
+
+// StoreScores records one row per model so models can be compared on the same tokens.
+func (d *SQLiteDB) StoreScores(mint string, scores []types.ScoreBreakdown) error {
+	now := time.Now().Unix()
+	for _, score := range scores {
+		data, err := json.Marshal(score)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s score: %w", score.Model, err)
+		}
+
+		_, err = d.conn.Exec(
+			`INSERT INTO token_scores (mint, model, total, data, created_at) VALUES (?, ?, ?, ?, ?)`,
+			mint, score.Model, score.Total, string(data), now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store %s score: %w", score.Model, err)
+		}
+	}
+	return nil
+}
+
+// LatestScores returns every model's score from the mint's most recent scoring.
+func (d *SQLiteDB) LatestScores(mint string) ([]types.ScoreBreakdown, error) {
+	rows, err := d.conn.Query(
+		`SELECT data FROM token_scores
+		WHERE mint = ? AND created_at = (SELECT MAX(created_at) FROM token_scores WHERE mint = ?)
+		ORDER BY id`, mint, mint,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make([]types.ScoreBreakdown, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan score: %w", err)
+		}
+
+		var score types.ScoreBreakdown
+		if err := json.Unmarshal([]byte(data), &score); err != nil {
+			return nil, fmt.Errorf("failed to decode score: %w", err)
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
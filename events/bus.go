@@ -0,0 +1,146 @@
+// Package events carries pipeline events from the services that produce them
+// to whoever is listening, such as the API's event stream.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type Kind string
+
+const (
+	PAIR_DISCOVERED Kind = "pair_discovered"
+	FILTER_PASSED   Kind = "filter_passed"
+	FILTER_REJECTED Kind = "filter_rejected"
+	SAFETY_REPORT   Kind = "safety_report"
+	BUY_SENT        Kind = "buy_sent"
+	BUY_CONFIRMED   Kind = "buy_confirmed"
+	POSITION_CLOSED Kind = "position_closed"
+	RUG_ALERT       Kind = "rug_alert"
+)
+
+// KINDS lists every kind of event, in pipeline order.
+var KINDS = []Kind{
+	PAIR_DISCOVERED, SAFETY_REPORT, FILTER_PASSED, FILTER_REJECTED,
+	BUY_SENT, BUY_CONFIRMED, POSITION_CLOSED, RUG_ALERT,
+}
+
+func (k Kind) Valid() bool {
+	for _, kind := range KINDS {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// HISTORY_SIZE events are kept so a reconnecting subscriber can catch up
+	HISTORY_SIZE = 256
+	// SUBSCRIBER_BUFFER events may queue for a subscriber before it misses some
+	SUBSCRIBER_BUFFER = 64
+)
+
+// Event is one thing that happened in the pipeline. Data is the kind's
+// payload: a pair, a safety report, an alert and so on.
+type Event struct {
+	ID     uint64    `json:"id"`
+	Kind   Kind      `json:"kind"`
+	Time   time.Time `json:"time"`
+	Mint   string    `json:"mint,omitempty"`
+	Symbol string    `json:"symbol,omitempty"`
+	Data   any       `json:"data,omitempty"`
+}
+
+type subscriber struct {
+	events chan Event
+	kinds  map[Kind]bool // Empty means every kind
+}
+
+func (s *subscriber) wants(kind Kind) bool {
+	return len(s.kinds) == 0 || s.kinds[kind]
+}
+
+// Bus fans events out to subscribers. Publishing never blocks: a subscriber
+// that falls behind misses events rather than stalling the pipeline.
+type Bus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	history     []Event
+	subscribers map[*subscriber]bool
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		history:     make([]Event, 0, HISTORY_SIZE),
+		subscribers: make(map[*subscriber]bool),
+	}
+}
+
+func (b *Bus) Publish(kind Kind, mint, symbol string, data any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := Event{ID: b.nextID, Kind: kind, Time: time.Now(), Mint: mint, Symbol: symbol, Data: data}
+
+	if len(b.history) == HISTORY_SIZE {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:HISTORY_SIZE-1]
+	}
+	b.history = append(b.history, event)
+
+	for sub := range b.subscribers {
+		if !sub.wants(kind) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns events of the given kinds, or of every kind if none are
+// given, starting with any kept events newer than afterID. Call cancel once
+// done to release the subscription; it closes the channel.
+func (b *Bus) Subscribe(afterID uint64, kinds ...Kind) (events <-chan Event, cancel func()) {
+	sub := &subscriber{events: make(chan Event, SUBSCRIBER_BUFFER+HISTORY_SIZE), kinds: make(map[Kind]bool)}
+	for _, kind := range kinds {
+		sub.kinds[kind] = true
+	}
+
+	b.mu.Lock()
+	if afterID > 0 {
+		for _, event := range b.history {
+			if event.ID > afterID && sub.wants(event.Kind) {
+				sub.events <- event
+			}
+		}
+	}
+	b.subscribers[sub] = true
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			close(sub.events)
+		})
+	}
+}
+
+var defaultBus = NewBus()
+
+// Default is the bus the services publish into.
+func Default() *Bus {
+	return defaultBus
+}
+
+// Publish sends an event on the default bus.
+func Publish(kind Kind, mint, symbol string, data any) {
+	defaultBus.Publish(kind, mint, symbol, data)
+}
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+// drain reads whatever is queued on events without waiting for more.
+func drain(events <-chan Event) []Event {
+	var got []Event
+	for {
+		select {
+		case event := <-events:
+			got = append(got, event)
+		default:
+			return got
+		}
+	}
+}
+
+func ids(events []Event) []uint64 {
+	got := make([]uint64, len(events))
+	for i, event := range events {
+		got[i] = event.ID
+	}
+	return got
+}
+
+func TestSubscribeReplaysHistory(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(PAIR_DISCOVERED, "a", "A", nil)
+	bus.Publish(FILTER_PASSED, "a", "A", nil)
+	bus.Publish(PAIR_DISCOVERED, "b", "B", nil)
+
+	// Last-Event-ID 1: everything after the first event, then live ones
+	events, cancel := bus.Subscribe(1)
+	defer cancel()
+	bus.Publish(BUY_SENT, "a", "A", nil)
+
+	if got := ids(drain(events)); len(got) != 3 || got[0] != 2 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("after id 1 got %v, want [2 3 4]", got)
+	}
+
+	// No Last-Event-ID: live events only
+	live, cancelLive := bus.Subscribe(0)
+	defer cancelLive()
+	if got := drain(live); len(got) != 0 {
+		t.Errorf("a new subscriber was replayed %v", ids(got))
+	}
+}
+
+func TestSubscribeHistoryIsBounded(t *testing.T) {
+	bus := NewBus()
+	for i := 0; i < HISTORY_SIZE+10; i++ {
+		bus.Publish(PAIR_DISCOVERED, "", "", i)
+	}
+
+	events, cancel := bus.Subscribe(1)
+	defer cancel()
+	got := drain(events)
+	if len(got) != HISTORY_SIZE || got[0].ID != 11 || got[len(got)-1].ID != HISTORY_SIZE+10 {
+		t.Errorf("replayed %d events from %d, want the last %d", len(got), got[0].ID, HISTORY_SIZE)
+	}
+}
+
+func TestSubscribeFiltersKinds(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(PAIR_DISCOVERED, "a", "A", nil)
+	bus.Publish(RUG_ALERT, "a", "A", nil)
+
+	events, cancel := bus.Subscribe(1, RUG_ALERT, BUY_CONFIRMED)
+	defer cancel()
+	bus.Publish(FILTER_REJECTED, "b", "B", nil)
+	bus.Publish(BUY_CONFIRMED, "a", "A", nil)
+
+	got := drain(events)
+	if len(got) != 2 || got[0].Kind != RUG_ALERT || got[1].Kind != BUY_CONFIRMED {
+		t.Errorf("got %+v, want the rug alert then the buy confirmation", got)
+	}
+}
+
+func TestPublishDropsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	slow, cancelSlow := bus.Subscribe(0)
+	defer cancelSlow()
+
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < cap(slow)+50; i++ {
+			bus.Publish(PAIR_DISCOVERED, "", "", i)
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a subscriber that is not reading")
+	}
+
+	got := drain(slow)
+	if len(got) != cap(slow) || got[0].ID != 1 {
+		t.Errorf("slow subscriber got %d events from %d, want the first %d", len(got), got[0].ID, cap(slow))
+	}
+
+	// A subscriber that keeps up still sees new events
+	fresh, cancelFresh := bus.Subscribe(0)
+	defer cancelFresh()
+	bus.Publish(RUG_ALERT, "", "", nil)
+	if got := drain(fresh); len(got) != 1 || got[0].Kind != RUG_ALERT {
+		t.Errorf("fresh subscriber got %+v", got)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	bus := NewBus()
+	events, cancel := bus.Subscribe(0)
+	cancel()
+	cancel() // Safe to call twice
+
+	if _, open := <-events; open {
+		t.Errorf("channel still open after cancel")
+	}
+	bus.Publish(PAIR_DISCOVERED, "", "", nil) // Must not send on the closed channel
+}
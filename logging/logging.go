@@ -0,0 +1,238 @@
+// Package logging sets up the structured logger shared by every component.
+//
+// Components take a logger with For once, at package level, and keep it. The
+// handler behind those loggers is swapped by Setup, so a config reload can
+// change levels and format without anything being rebuilt.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	FORMAT_TEXT = "text"
+	FORMAT_JSON = "json"
+
+	// DEFAULT_SAMPLE_EVERY keeps one in this many records on sampled paths
+	DEFAULT_SAMPLE_EVERY = 1000
+)
+
+// Config picks the log level, per-component overrides and output format.
+type Config struct {
+	Level  string `json:"level"`  // debug, info, warn or error
+	Format string `json:"format"` // text or json
+	// Components overrides the level by component, e.g. {"raydium": "debug"}
+	Components map[string]string `json:"components,omitempty"`
+	// SampleEvery keeps one in this many debug and info records on hot paths
+	// such as pair validation. 1 keeps them all.
+	SampleEvery int `json:"sampleEvery"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Level:       "info",
+		Format:      FORMAT_TEXT,
+		SampleEvery: DEFAULT_SAMPLE_EVERY,
+	}
+}
+
+// Validate reports the first setting that Setup would reject.
+func (c Config) Validate() error {
+	if _, err := ParseLevel(c.Level); err != nil {
+		return err
+	}
+	for component, level := range c.Components {
+		if _, err := ParseLevel(level); err != nil {
+			return fmt.Errorf("component %s: %w", component, err)
+		}
+	}
+	if c.Format != FORMAT_TEXT && c.Format != FORMAT_JSON {
+		return fmt.Errorf("unknown format %q, expected %s or %s", c.Format, FORMAT_TEXT, FORMAT_JSON)
+	}
+	if c.SampleEvery < 1 {
+		return fmt.Errorf("sampleEvery must be at least 1")
+	}
+	return nil
+}
+
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
+// state is everything Setup replaces in one go.
+type state struct {
+	handler     slog.Handler
+	level       slog.Level
+	components  map[string]slog.Level
+	sampleEvery uint64
+}
+
+var (
+	setupMu sync.Mutex
+	output  io.Writer = os.Stderr
+	current atomic.Pointer[state]
+)
+
+func init() {
+	current.Store(&state{
+		handler:     slog.NewTextHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		level:       slog.LevelInfo,
+		sampleEvery: DEFAULT_SAMPLE_EVERY,
+	})
+}
+
+// Setup applies config to every logger, including ones already handed out,
+// and routes the standard library's log package through it as well.
+func Setup(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	level, _ := ParseLevel(config.Level)
+	components := make(map[string]slog.Level, len(config.Components))
+	for component, name := range config.Components {
+		components[component], _ = ParseLevel(name)
+	}
+
+	setupMu.Lock()
+	defer setupMu.Unlock()
+
+	// Levels are filtered per component, so the handler itself lets everything through
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler
+	if config.Format == FORMAT_JSON {
+		handler = slog.NewJSONHandler(output, options)
+	} else {
+		handler = slog.NewTextHandler(output, options)
+	}
+
+	current.Store(&state{
+		handler:     handler,
+		level:       level,
+		components:  components,
+		sampleEvery: uint64(config.SampleEvery),
+	})
+	slog.SetDefault(For("main"))
+	log.SetFlags(0) // slog adds its own timestamp
+	return nil
+}
+
+// SetOutput redirects all loggers, mainly for tests. It takes effect on the
+// next Setup.
+func SetOutput(w io.Writer) {
+	setupMu.Lock()
+	output = w
+	setupMu.Unlock()
+}
+
+// For returns the logger for a component. Every record it writes carries the
+// component's name, and the component's level override applies to it.
+func For(component string) *slog.Logger {
+	return slog.New(&componentHandler{component: component})
+}
+
+// Sampled returns a logger that writes only one in every SampleEvery debug
+// and info records with the same message. Warnings and errors always get
+// through.
+func Sampled(logger *slog.Logger) *slog.Logger {
+	return slog.New(&samplingHandler{next: logger.Handler(), counts: &sync.Map{}})
+}
+
+// componentHandler forwards to whatever handler Setup last installed. With
+// and WithGroup calls are recorded and replayed on it, since it may change.
+type componentHandler struct {
+	component string
+	ops       []func(slog.Handler) slog.Handler
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	s := current.Load()
+	min, ok := s.components[h.component]
+	if !ok {
+		min = s.level
+	}
+	return level >= min
+}
+
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler := current.Load().handler.WithAttrs([]slog.Attr{slog.String("component", h.component)})
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	return handler.Handle(ctx, record)
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *componentHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &componentHandler{component: h.component, ops: append(ops, op)}
+}
+
+// samplingHandler counts records by message and passes every Nth one on.
+// Loggers derived with With share the counts.
+type samplingHandler struct {
+	next   slog.Handler
+	counts *sync.Map // Message -> *atomic.Uint64
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	every := current.Load().sampleEvery
+	if record.Level >= slog.LevelWarn || every <= 1 {
+		return h.next.Handle(ctx, record)
+	}
+
+	counter, _ := h.counts.LoadOrStore(record.Message, new(atomic.Uint64))
+	n := counter.(*atomic.Uint64).Add(1)
+	if (n-1)%every != 0 {
+		return nil
+	}
+	record.AddAttrs(slog.Uint64("sampled", every))
+	return h.next.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), counts: h.counts}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), counts: h.counts}
+}
+
+// Token is the fields that identify a token, for logger.With.
+func Token(mint, symbol, pool string) []any {
+	fields := []any{slog.String("mint", mint)}
+	if symbol != "" {
+		fields = append(fields, slog.String("symbol", symbol))
+	}
+	if pool != "" {
+		fields = append(fields, slog.String("pool", pool))
+	}
+	return fields
+}
+
+// Err is the conventional field for an error.
+func Err(err error) slog.Attr {
+	return slog.Any("err", err)
+}
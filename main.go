@@ -1,29 +1,71 @@
 package main
 
 import (
-	"context"
-	"grind/services"
+	"fmt"
+	"grind/logging"
+	"io"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
+	"text/tabwriter"
 )
 
+const CONFIG_PATH = "config.json"
+
+var mainLog = logging.For("main")
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"scan", "scan", "watch for new tokens, track and guard positions (the default)", runScan},
+	{"check", "check <mint>", "run the safety checks on one token and print the report", runCheck},
+	{"buy", "buy <mint> <sol>", "buy a token with SOL, after confirming", runBuy},
+	{"sell", "sell <mint> <amount|all>", "sell tokens from a position, after confirming", runSell},
+	{"positions", "positions", "list open positions", runPositions},
+	{"backtest", "backtest", "replay recorded observations against filter configurations", runBacktest},
+	{"preview", "preview [template...]", "render notification templates against a sample pair", runPreview},
+	{"config", "config", "print the effective config, secrets masked", runShowConfig},
+}
+
 func main() {
-	// Setup signal handling for graceful shutdown
-	_, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// With no command grind scans, as it always has
+	name, args := "scan", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
 
-	// Create channels
-	tokenChan := make(chan services.RaydiumPair, 100)
+	switch name {
+	case "help", "-h", "-help", "--help":
+		usage(os.Stdout)
+		return
+	}
 
-	// Start services
-	go services.ProcessNewTokens(tokenChan)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				log.Fatalf("%s: %v", name, err)
+			}
+			return
+		}
+	}
 
-	// Wait for shutdown signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(2)
+}
 
-	log.Println("Shutting down gracefully...")
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: grind <command> [flags]")
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.usage, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run grind <command> -h for a command's flags.")
 }
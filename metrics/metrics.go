@@ -0,0 +1,150 @@
+// Package metrics exposes the scanner pipeline's counters, gauges and
+// histograms for Prometheus. The metrics are always recorded; serving them is
+// opt-in.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const NAMESPACE = "grind"
+
+// API names used as the api label.
+const (
+	API_RAYDIUM_PAIRS = "raydium_pairs"
+	API_GOPLUS        = "goplus"
+)
+
+// Buy outcomes used as the outcome label.
+const (
+	BUY_SENT                 = "sent"
+	BUY_FAILED               = "failed"
+	BUY_INSUFFICIENT_BALANCE = "insufficient_balance"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	CycleDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "cycle_duration_seconds",
+		Help:      "Time taken by one token fetch and evaluation cycle.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600},
+	})
+	PairsFetched = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Name:      "pairs_fetched",
+		Help:      "Pairs in the last Raydium feed fetched, by validity.",
+	}, []string{"status"})
+	TokensEvaluated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "tokens_evaluated_total",
+		Help:      "New tokens run through the safety filter.",
+	})
+	TokensRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "tokens_rejected_total",
+		Help:      "Filter rejections by blocking check. A token failing several checks counts once per check.",
+	}, []string{"check"})
+	TokensPassed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "tokens_passed_total",
+		Help:      "New tokens that passed the safety filter.",
+	})
+	TrackedTokens = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Name:      "tracked_tokens",
+		Help:      "Tokens currently being tracked.",
+	})
+	APIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "api_request_duration_seconds",
+		Help:      "Latency of external API requests, including failed ones.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"api"})
+	APIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "api_errors_total",
+		Help:      "External API requests that failed.",
+	}, []string{"api"})
+	SafetyCheckDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "safety_check_duration_seconds",
+		Help:      "Time taken to gather safety data for one token.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	})
+	SafetyDataUnavailable = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "safety_data_unavailable_total",
+		Help:      "Safety checks whose data source could not answer.",
+	}, []string{"check"})
+	Buys = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "buys_total",
+		Help:      "Buy attempts by outcome.",
+	}, []string{"outcome"})
+	NotificationDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "notification_drops_total",
+		Help:      "Notifications dropped because a channel's queue was full.",
+	}, []string{"channel"})
+	NotificationsDeduplicated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "notifications_deduplicated_total",
+		Help:      "Notifications suppressed as repeats within the dedup window.",
+	})
+	NotificationsThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "notifications_throttled_total",
+		Help:      "Low-priority notifications dropped by a channel's rate limit.",
+	}, []string{"channel"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		CycleDuration, PairsFetched, TokensEvaluated, TokensRejected, TokensPassed,
+		TrackedTokens, APIRequestDuration, APIErrors, SafetyCheckDuration, SafetyDataUnavailable, Buys,
+		NotificationDrops, NotificationsDeduplicated, NotificationsThrottled,
+	)
+}
+
+// ObserveAPI records one request to api that started at start.
+func ObserveAPI(api string, start time.Time, err error) {
+	APIRequestDuration.WithLabelValues(api).Observe(time.Since(start).Seconds())
+	if err != nil {
+		APIErrors.WithLabelValues(api).Inc()
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// Serve exposes /metrics on addr until ctx is cancelled.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
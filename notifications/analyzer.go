@@ -1,13 +1,61 @@
 package notifications
 
+import (
+	"net/http"
+	"sync"
+)
+
 type TelegramNotifier struct {
-	botKey string
-	chatID string
+	templated
+	mu         sync.RWMutex
+	botKey     string
+	chatID     string
+	baseURL    string
+	client     *http.Client
+	buyPresets []float64 // SOL amounts offered as buy buttons on passing tokens
+	maxBuy     float64   // Most SOL the bot may spend on one buy; none without it
 }
 
 func NewTelegramNotifier(botKey, chatID string) *TelegramNotifier {
 	return &TelegramNotifier{
-		botKey: botKey,
-		chatID: chatID,
+		botKey:  botKey,
+		chatID:  chatID,
+		baseURL: TELEGRAM_API_URL,
+		client:  &http.Client{},
 	}
 }
+
+// SetCredentials switches the bot and chat messages are sent with.
+func (t *TelegramNotifier) SetCredentials(botKey, chatID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.botKey = botKey
+	t.chatID = chatID
+}
+
+func (t *TelegramNotifier) SetBaseURL(baseURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.baseURL = baseURL
+}
+
+// SetBuyPresets sets the buy buttons shown on passing tokens. None hides them,
+// as when the bot is not taking commands.
+func (t *TelegramNotifier) SetBuyPresets(presets []float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buyPresets = presets
+}
+
+// SetMaxBuy caps the SOL one buy from the bot may spend.
+func (t *TelegramNotifier) SetMaxBuy(sol float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxBuy = sol
+}
+
+func (t *TelegramNotifier) maxBuyAmount() float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.maxBuy
+}
@@ -0,0 +1,213 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"grind/logging"
+	"grind/types"
+)
+
+// Discord limits, see https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	DISCORD_CONTENT_LIMIT     = 2000
+	DISCORD_DESCRIPTION_LIMIT = 4096
+	DISCORD_FIELD_LIMIT       = 1024
+)
+
+// Embed colours
+const (
+	DISCORD_GREEN  = 0x2ecc71
+	DISCORD_RED    = 0xe74c3c
+	DISCORD_YELLOW = 0xf1c40f
+	DISCORD_BLUE   = 0x3498db
+)
+
+var discordLog = logging.For("discord")
+
+var discordSeverityColors = map[types.AlertSeverity]int{
+	types.SeverityInfo:     DISCORD_BLUE,
+	types.SeverityWarning:  DISCORD_YELLOW,
+	types.SeverityCritical: DISCORD_RED,
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// DiscordNotifier posts embeds to a Discord incoming webhook, or plain
+// messages for notifications it has a template for.
+type DiscordNotifier struct {
+	templated
+	webhook *webhook
+}
+
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{webhook: newWebhook(webhookURL, discordLog)}
+}
+
+func (d *DiscordNotifier) SetWebhookURL(webhookURL string) {
+	d.webhook.setURL(webhookURL)
+}
+
+func (d *DiscordNotifier) NotifyNewPair(pair types.RaydiumPair) {
+	if d.sendTemplate(TEMPLATE_NEW_PAIR, pair) {
+		return
+	}
+	d.send(discordEmbed{
+		Title:       fmt.Sprintf("🚀 New token: %s", pair.Symbol),
+		Description: pair.Name,
+		URL:         DEXSCREENER_TOKEN_URL + pair.Address,
+		Color:       DISCORD_BLUE,
+		Fields: []discordField{
+			{Name: "Liquidity", Value: FormatUSD(pair.Liquidity), Inline: true},
+			{Name: "Market cap", Value: FormatUSD(pair.MarketCap), Inline: true},
+			{Name: "Mint", Value: "`" + pair.Address + "`"},
+			{Name: "Links", Value: discordLinks(TokenLinks(pair.Address))},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+func (d *DiscordNotifier) NotifySafetyReport(token types.TokenReport) {
+	if d.sendTemplate(TEMPLATE_SAFETY_REPORT, &token) {
+		return
+	}
+	pair, report := token.Pair, token.Report
+	title, color := fmt.Sprintf("🔥 %s passed filters", pair.Symbol), DISCORD_GREEN
+	if !report.Passed() {
+		title, color = fmt.Sprintf("🛡 %s rejected", pair.Symbol), DISCORD_RED
+	}
+
+	score := "n/a"
+	if breakdown, ok := token.Score(); ok {
+		score = fmt.Sprintf("%.1f (%s)", breakdown.Total, breakdown.Model)
+	}
+
+	d.send(discordEmbed{
+		Title:       title,
+		Description: truncate(pair.Name+"\n```\n"+FormatCheckTable(report), DISCORD_DESCRIPTION_LIMIT-len("\n```")) + "\n```",
+		URL:         DEXSCREENER_TOKEN_URL + pair.Address,
+		Color:       color,
+		Fields: []discordField{
+			{Name: "Liquidity", Value: FormatUSD(pair.Liquidity), Inline: true},
+			{Name: "Market cap", Value: FormatUSD(pair.MarketCap), Inline: true},
+			{Name: "Holders", Value: fmt.Sprint(token.Safety.HolderCount), Inline: true},
+			{Name: "Score", Value: score, Inline: true},
+			{Name: "Mint", Value: "`" + pair.Address + "`"},
+			{Name: "Links", Value: discordLinks(TokenLinks(pair.Address))},
+		},
+		Timestamp: report.GeneratedAt.Format(time.RFC3339),
+	})
+}
+
+func (d *DiscordNotifier) NotifyAlert(alert types.Alert) {
+	if d.sendTemplate(TEMPLATE_ALERT, alert) {
+		return
+	}
+	d.send(discordEmbed{
+		Title:       fmt.Sprintf("%s %s: %s", alertIcons[alert.Severity], strings.ToUpper(string(alert.Severity)), alert.Symbol),
+		Description: truncate(alert.Message, DISCORD_DESCRIPTION_LIMIT),
+		URL:         DEXSCREENER_TOKEN_URL + alert.Mint,
+		Color:       discordSeverityColors[alert.Severity],
+		Fields: []discordField{
+			{Name: "Kind", Value: alert.Kind, Inline: true},
+			{Name: "Mint", Value: "`" + alert.Mint + "`"},
+			{Name: "Links", Value: discordLinks(TokenLinks(alert.Mint))},
+		},
+		Timestamp: alert.Time.Format(time.RFC3339),
+	})
+}
+
+func (d *DiscordNotifier) NotifyTrade(trade types.Trade) {
+	if d.sendTemplate(TEMPLATE_TRADE, trade) {
+		return
+	}
+	embed := discordEmbed{
+		URL:       DEXSCREENER_TOKEN_URL + trade.Mint,
+		Timestamp: trade.Time.Format(time.RFC3339),
+	}
+	amount := fmt.Sprintf("%g %s", trade.Amount, trade.Symbol)
+	if trade.Side == types.TradeBuy {
+		amount = fmt.Sprintf("%g SOL", trade.Amount)
+	}
+
+	switch {
+	case trade.Error != "":
+		embed.Title, embed.Color = fmt.Sprintf("🚨 Failed to %s %s", trade.Side, trade.Symbol), DISCORD_RED
+		embed.Description = truncate(trade.Error, DISCORD_DESCRIPTION_LIMIT)
+	case trade.Side == types.TradeBuy:
+		embed.Title, embed.Color = fmt.Sprintf("🟢 Bought %s", trade.Symbol), DISCORD_GREEN
+	default:
+		embed.Title, embed.Color = fmt.Sprintf("🔴 Sold %s", trade.Symbol), DISCORD_YELLOW
+	}
+
+	embed.Fields = append(embed.Fields, discordField{Name: "Amount", Value: amount, Inline: true})
+	if trade.Reason != "" {
+		embed.Fields = append(embed.Fields, discordField{Name: "Reason", Value: truncate(trade.Reason, DISCORD_FIELD_LIMIT), Inline: true})
+	}
+	links := TokenLinks(trade.Mint)
+	if trade.Signature != "" {
+		links = append([]Link{TxLink(trade.Signature)}, links...)
+	}
+	embed.Fields = append(embed.Fields, discordField{Name: "Links", Value: discordLinks(links)})
+	d.send(embed)
+}
+
+func (d *DiscordNotifier) NotifyDigest(digest Digest) {
+	if d.sendTemplate(TEMPLATE_DIGEST, digest) {
+		return
+	}
+	title, lines, _ := strings.Cut(FormatDigest(digest), "\n")
+	d.send(discordEmbed{
+		Title:       title,
+		Description: truncate(lines, DISCORD_DESCRIPTION_LIMIT),
+		Color:       DISCORD_BLUE,
+		Timestamp:   digest.Until.Format(time.RFC3339),
+	})
+}
+
+// sendTemplate sends the named template as a plain message, reporting false
+// when there is none.
+func (d *DiscordNotifier) sendTemplate(name string, data any) bool {
+	message, ok := d.render(name, data)
+	if ok {
+		d.post(discordMessage{Content: truncate(message, DISCORD_CONTENT_LIMIT)})
+	}
+	return ok
+}
+
+func (d *DiscordNotifier) send(embed discordEmbed) {
+	d.post(discordMessage{Embeds: []discordEmbed{embed}})
+}
+
+func (d *DiscordNotifier) post(message discordMessage) {
+	if err := d.webhook.post(message); err != nil {
+		discordLog.Error("Failed to send discord notification", logging.Err(err))
+	}
+}
+
+func discordLinks(links []Link) string {
+	parts := make([]string, 0, len(links))
+	for _, link := range links {
+		parts = append(parts, fmt.Sprintf("[%s](%s)", link.Name, link.URL))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"grind/types"
+)
+
+func decodeDiscord(t *testing.T, body string) discordEmbed {
+	t.Helper()
+	var message discordMessage
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	if len(message.Embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(message.Embeds))
+	}
+	return message.Embeds[0]
+}
+
+func discordFieldValue(embed discordEmbed, name string) string {
+	for _, field := range embed.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+func TestDiscordSafetyReportEmbed(t *testing.T) {
+	stub := newWebhookStub(t)
+	NewDiscordNotifier(stub.URL).NotifySafetyReport(sampleToken())
+
+	posts := stub.posts()
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	embed := decodeDiscord(t, posts[0])
+
+	if embed.Title != "🔥 POPCAT passed filters" || embed.Color != DISCORD_GREEN {
+		t.Errorf("got title %q colour %#x", embed.Title, embed.Color)
+	}
+	for name, want := range map[string]string{
+		"Liquidity":  "$48.3K",
+		"Market cap": "$1.25M",
+		"Holders":    "412",
+		"Score":      "72.5 (heuristic)",
+	} {
+		if got := discordFieldValue(embed, name); got != want {
+			t.Errorf("field %s = %q, want %q", name, got, want)
+		}
+	}
+	for _, want := range []string{"```", "PASS  holder_count", "WARN  social_presence  no twitter"} {
+		if !strings.Contains(embed.Description, want) {
+			t.Errorf("description %q is missing %q", embed.Description, want)
+		}
+	}
+	if links := discordFieldValue(embed, "Links"); !strings.Contains(links, "[Solscan](https://solscan.io/token/"+sampleMint+")") {
+		t.Errorf("links %q are missing Solscan", links)
+	}
+}
+
+func TestDiscordRejectedReportIsRed(t *testing.T) {
+	token := sampleToken()
+	token.Report.Add(types.SafetyCheck{Name: "honeypot", Status: types.CheckFail, Value: "true", Source: "goplus"})
+	token.Scores = nil
+
+	stub := newWebhookStub(t)
+	NewDiscordNotifier(stub.URL).NotifySafetyReport(token)
+
+	embed := decodeDiscord(t, stub.posts()[0])
+	if embed.Title != "🛡 POPCAT rejected" || embed.Color != DISCORD_RED {
+		t.Errorf("got title %q colour %#x", embed.Title, embed.Color)
+	}
+	if score := discordFieldValue(embed, "Score"); score != "n/a" {
+		t.Errorf("score = %q, want n/a", score)
+	}
+}
+
+func TestDiscordTradeLinksTransaction(t *testing.T) {
+	stub := newWebhookStub(t)
+	NewDiscordNotifier(stub.URL).NotifyTrade(types.Trade{Side: types.TradeBuy, Mint: sampleMint, Symbol: "POPCAT",
+		Amount: 0.5, Signature: "5sig"})
+
+	embed := decodeDiscord(t, stub.posts()[0])
+	if embed.Title != "🟢 Bought POPCAT" || discordFieldValue(embed, "Amount") != "0.5 SOL" {
+		t.Errorf("got title %q amount %q", embed.Title, discordFieldValue(embed, "Amount"))
+	}
+	if links := discordFieldValue(embed, "Links"); !strings.HasPrefix(links, "[Transaction](https://solscan.io/tx/5sig)") {
+		t.Errorf("links %q do not start with the transaction", links)
+	}
+}
+
+func TestDiscordTruncatesLongReports(t *testing.T) {
+	token := sampleToken()
+	for i := 0; i < 200; i++ {
+		token.Report.Add(types.SafetyCheck{Name: "check", Status: types.CheckPass, Value: strings.Repeat("x", 40), Source: "test"})
+	}
+
+	stub := newWebhookStub(t)
+	NewDiscordNotifier(stub.URL).NotifySafetyReport(token)
+
+	embed := decodeDiscord(t, stub.posts()[0])
+	if len(embed.Description) > DISCORD_DESCRIPTION_LIMIT || !strings.HasSuffix(embed.Description, "…\n```") {
+		t.Errorf("description is %d bytes ending %q", len(embed.Description), embed.Description[len(embed.Description)-10:])
+	}
+}
@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"grind/types"
+)
+
+var statusIcons = map[types.CheckStatus]string{
+	types.CheckPass:    "✅",
+	types.CheckFail:    "❌",
+	types.CheckWarn:    "⚠️",
+	types.CheckUnknown: "❔",
+}
+
+// FormatSafetyReport renders one line per check, e.g. "❌ holder_count: 42 (>= 100) [solscan]".
+func FormatSafetyReport(report types.SafetyReport) string {
+	var b strings.Builder
+	for _, check := range report.Checks {
+		fmt.Fprintf(&b, "%s %s", statusIcons[check.Status], check.Name)
+		if check.Value != "" {
+			fmt.Fprintf(&b, ": %s", check.Value)
+		}
+		if check.Threshold != "" {
+			fmt.Fprintf(&b, " (%s)", check.Threshold)
+		}
+		fmt.Fprintf(&b, " [%s]\n", check.Source)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+const (
+	SOLSCAN_TOKEN_URL     = "https://solscan.io/token/"
+	SOLSCAN_TX_URL        = "https://solscan.io/tx/"
+	BIRDEYE_TOKEN_URL     = "https://birdeye.so/token/"
+	DEXSCREENER_TOKEN_URL = "https://dexscreener.com/solana/"
+)
+
+// Link is a named URL, rendered in each channel's own markup.
+type Link struct {
+	Name string
+	URL  string
+}
+
+// TokenLinks are the explorer and chart pages for a mint.
+func TokenLinks(mint string) []Link {
+	return []Link{
+		{"Solscan", SOLSCAN_TOKEN_URL + mint},
+		{"Birdeye", BIRDEYE_TOKEN_URL + mint + "?chain=solana"},
+		{"DexScreener", DEXSCREENER_TOKEN_URL + mint},
+	}
+}
+
+func TxLink(signature string) Link {
+	return Link{"Transaction", SOLSCAN_TX_URL + signature}
+}
+
+// FormatUSD abbreviates large amounts, e.g. $1.25M, $48.3K, $512.40.
+func FormatUSD(amount float64) string {
+	switch abs := math.Abs(amount); {
+	case abs >= 1e9:
+		return fmt.Sprintf("$%.2fB", amount/1e9)
+	case abs >= 1e6:
+		return fmt.Sprintf("$%.2fM", amount/1e6)
+	case abs >= 1e4:
+		return fmt.Sprintf("$%.1fK", amount/1e3)
+	default:
+		return fmt.Sprintf("$%.2f", amount)
+	}
+}
+
+// FormatCheckTable renders the checks as aligned plain text, for code blocks
+// where emoji would break the alignment.
+func FormatCheckTable(report types.SafetyReport) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, check := range report.Checks {
+		detail := check.Value
+		if check.Threshold != "" {
+			detail = strings.TrimSpace(detail + " (" + check.Threshold + ")")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(string(check.Status)), check.Name, detail)
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// truncate cuts s to at most limit bytes on a line break where it can, for
+// channels that reject long fields.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	const more = "\n…"
+	cut := s[:limit-len(more)]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.ToValidUTF8(cut, "") + more
+}
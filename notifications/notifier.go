@@ -0,0 +1,303 @@
+package notifications
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"grind/logging"
+	"grind/metrics"
+	"grind/types"
+)
+
+// NOTIFY_QUEUE_SIZE is how many notifications a channel can fall behind by
+// before new ones are dropped for it.
+const NOTIFY_QUEUE_SIZE = 64
+
+var notifyLog = logging.For("notify")
+
+// Notifier is a notification channel such as Telegram.
+type Notifier interface {
+	NotifyNewPair(pair types.RaydiumPair)
+	NotifySafetyReport(token types.TokenReport)
+	NotifyAlert(alert types.Alert)
+	NotifyTrade(trade types.Trade)
+	NotifyDigest(digest Digest)
+}
+
+// channel is one backend with its own queue and delivery goroutine, so a slow
+// or broken backend only holds up itself.
+type channel struct {
+	name     string
+	notifier Notifier
+	options  ChannelOptions
+	queue    chan func(Notifier)
+	bucket   bucket
+	digest   Digest // Pending entries when options.Digest is set
+}
+
+// Multiplexer fans notifications out to every channel whose minimum severity
+// they meet. Repeats within the dedup window are dropped. Urgent notifications,
+// alerts from warning up and trades, go out at once; others are rate limited
+// per channel, or batched into digests for channels that want them.
+//
+// It never blocks the caller: a full queue drops the notification for that
+// channel.
+type Multiplexer struct {
+	mu          sync.Mutex
+	channels    []*channel
+	dedupWindow time.Duration
+	seen        map[string]time.Time
+	lastPrune   time.Time
+	digests     *time.Ticker
+	closed      bool
+	stop        chan struct{}
+	wg          sync.WaitGroup
+}
+
+func NewMultiplexer() *Multiplexer {
+	m := &Multiplexer{
+		dedupWindow: DEFAULT_DEDUP_WINDOW,
+		seen:        make(map[string]time.Time),
+		digests:     time.NewTicker(DEFAULT_DIGEST_INTERVAL),
+		stop:        make(chan struct{}),
+	}
+	go m.sendDigests()
+	return m
+}
+
+// ValidatePolicy reports whether SetPolicy would accept the dedup window and
+// digest interval.
+func ValidatePolicy(dedupWindow, digestInterval time.Duration) error {
+	if dedupWindow < 0 || digestInterval <= 0 {
+		return fmt.Errorf("invalid dedup window %v or digest interval %v", dedupWindow, digestInterval)
+	}
+	return nil
+}
+
+// SetPolicy changes the dedup window, 0 for none, and the digest interval.
+func (m *Multiplexer) SetPolicy(dedupWindow, digestInterval time.Duration) error {
+	if err := ValidatePolicy(dedupWindow, digestInterval); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dedupWindow = dedupWindow
+	m.digests.Reset(digestInterval)
+	return nil
+}
+
+// Add starts delivering notifications to notifier as options say.
+func (m *Multiplexer) Add(name string, notifier Notifier, options ChannelOptions) error {
+	if err := options.Validate(name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return fmt.Errorf("notifier closed")
+	}
+	for _, ch := range m.channels {
+		if ch.name == name {
+			return fmt.Errorf("duplicate notification channel %q", name)
+		}
+	}
+
+	ch := &channel{
+		name:     name,
+		notifier: notifier,
+		options:  options,
+		queue:    make(chan func(Notifier), NOTIFY_QUEUE_SIZE),
+	}
+	m.channels = append(m.channels, ch)
+	m.wg.Add(1)
+	go m.deliver(ch)
+	return nil
+}
+
+// SetOptions changes the options of the named channel. Turning its digest off
+// sends what it holds.
+func (m *Multiplexer) SetOptions(name string, options ChannelOptions) error {
+	if err := options.Validate(name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.channels {
+		if ch.name == name {
+			ch.options = options
+			if !options.Digest && !m.closed {
+				m.flushDigest(ch, time.Now())
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown notification channel %q", name)
+}
+
+// Validate reports whether the options can be used for the named channel.
+func (o ChannelOptions) Validate(name string) error {
+	if !o.MinSeverity.Valid() {
+		return fmt.Errorf("unknown severity %q for %s", o.MinSeverity, name)
+	}
+	if o.MaxPerMinute < 0 {
+		return fmt.Errorf("negative rate limit for %s", name)
+	}
+	return nil
+}
+
+// Close sends what is queued and any pending digests, and stops the channels.
+// Later notifications are dropped.
+func (m *Multiplexer) Close() {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.stop)
+		m.digests.Stop()
+		now := time.Now()
+		for _, ch := range m.channels {
+			m.flushDigest(ch, now)
+			close(ch.queue)
+		}
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
+
+func (m *Multiplexer) NotifyNewPair(pair types.RaydiumPair) {
+	m.dispatch(newPairNotification(pair))
+}
+
+func (m *Multiplexer) NotifySafetyReport(token types.TokenReport) {
+	m.dispatch(safetyReportNotification(token))
+}
+
+func (m *Multiplexer) NotifyAlert(alert types.Alert) {
+	m.dispatch(alertNotification(alert))
+}
+
+func (m *Multiplexer) NotifyTrade(trade types.Trade) {
+	m.dispatch(tradeNotification(trade))
+}
+
+func (m *Multiplexer) dispatch(n notification) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+
+	now := time.Now()
+	if m.repeat(n.key, now) {
+		metrics.NotificationsDeduplicated.Inc()
+		notifyLog.Debug("Dropped repeat notification", "kind", n.kind, "key", n.key)
+		return
+	}
+
+	for _, ch := range m.channels {
+		if !n.severity.AtLeast(ch.options.MinSeverity) {
+			continue
+		}
+		switch {
+		case n.urgent:
+			m.enqueue(ch, n.kind, n.send)
+		case ch.options.Digest:
+			ch.addToDigest(n.entry, n.kind, now)
+		case !ch.bucket.take(ch.options.MaxPerMinute, now):
+			metrics.NotificationsThrottled.WithLabelValues(ch.name).Inc()
+			notifyLog.Debug("Throttled notification", "channel", ch.name, "kind", n.kind)
+		default:
+			m.enqueue(ch, n.kind, n.send)
+		}
+	}
+}
+
+// repeat reports whether key was seen within the dedup window, and records it.
+func (m *Multiplexer) repeat(key string, now time.Time) bool {
+	if key == "" || m.dedupWindow == 0 {
+		return false
+	}
+	if now.Sub(m.lastPrune) > m.dedupWindow {
+		for seenKey, at := range m.seen {
+			if now.Sub(at) > m.dedupWindow {
+				delete(m.seen, seenKey)
+			}
+		}
+		m.lastPrune = now
+	}
+
+	if at, ok := m.seen[key]; ok && now.Sub(at) <= m.dedupWindow {
+		return true
+	}
+	m.seen[key] = now
+	return false
+}
+
+func (ch *channel) addToDigest(entry DigestEntry, kind string, now time.Time) {
+	if ch.digest.Since.IsZero() {
+		ch.digest.Since = now
+	}
+	if len(ch.digest.Entries) >= DIGEST_MAX_ENTRIES {
+		ch.digest.Omitted++
+		return
+	}
+	entry.Time, entry.Kind = now, kind
+	ch.digest.Entries = append(ch.digest.Entries, entry)
+}
+
+func (m *Multiplexer) sendDigests() {
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-m.digests.C:
+			m.mu.Lock()
+			if !m.closed {
+				for _, ch := range m.channels {
+					m.flushDigest(ch, now)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+// flushDigest queues the channel's pending digest, if it has one; callers hold the lock.
+func (m *Multiplexer) flushDigest(ch *channel, now time.Time) {
+	if len(ch.digest.Entries) == 0 {
+		return
+	}
+	digest := ch.digest
+	digest.Until = now
+	ch.digest = Digest{}
+	m.enqueue(ch, "digest", func(n Notifier) { n.NotifyDigest(digest) })
+}
+
+func (m *Multiplexer) enqueue(ch *channel, kind string, send func(Notifier)) {
+	select {
+	case ch.queue <- send:
+	default:
+		metrics.NotificationDrops.WithLabelValues(ch.name).Inc()
+		notifyLog.Warn("Notification queue full, dropping", "channel", ch.name, "kind", kind)
+	}
+}
+
+func (m *Multiplexer) deliver(ch *channel) {
+	defer m.wg.Done()
+	for send := range ch.queue {
+		m.send(ch, send)
+	}
+}
+
+// send delivers one notification, containing any panic to the channel it came from.
+func (m *Multiplexer) send(ch *channel, send func(Notifier)) {
+	defer func() {
+		if r := recover(); r != nil {
+			notifyLog.Error("Notification channel panicked", "channel", ch.name, "panic", r)
+		}
+	}()
+	send(ch.notifier)
+}
@@ -0,0 +1,293 @@
+package notifications
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+// recorder is a Notifier that notes what it was sent.
+type recorder struct {
+	mu      sync.Mutex
+	pairs   []string
+	reports int
+	alerts  []types.AlertSeverity
+	trades  int
+	digests []Digest
+}
+
+func (r *recorder) NotifyNewPair(pair types.RaydiumPair) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pairs = append(r.pairs, pair.Address)
+}
+
+func (r *recorder) NotifySafetyReport(token types.TokenReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports++
+}
+
+func (r *recorder) NotifyAlert(alert types.Alert) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert.Severity)
+}
+
+func (r *recorder) NotifyTrade(trade types.Trade) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trades++
+}
+
+func (r *recorder) NotifyDigest(digest Digest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digests = append(r.digests, digest)
+}
+
+func newRecorded(t *testing.T, options ChannelOptions) (*Multiplexer, *recorder) {
+	t.Helper()
+	m := NewMultiplexer()
+	r := &recorder{}
+	if err := m.Add("test", r, options); err != nil {
+		t.Fatal(err)
+	}
+	return m, r
+}
+
+func pair(n int) types.RaydiumPair {
+	return types.RaydiumPair{Symbol: fmt.Sprintf("TOK%d", n), Address: fmt.Sprintf("mint%d", n), Liquidity: 20000}
+}
+
+func rugAlert(severity types.AlertSeverity) types.Alert {
+	return types.Alert{Kind: "liquidity_drop", Severity: severity, Mint: sampleMint, Symbol: "POPCAT", Message: "liquidity fell", Time: time.Now()}
+}
+
+func TestMultiplexerDropsRepeats(t *testing.T) {
+	m, r := newRecorded(t, ChannelOptions{MinSeverity: types.SeverityInfo})
+
+	m.NotifyNewPair(pair(1))
+	m.NotifyNewPair(pair(1))
+	m.NotifyNewPair(pair(2))
+	m.NotifySafetyReport(sampleToken())
+	m.NotifySafetyReport(sampleToken())
+	m.Close()
+
+	if len(r.pairs) != 2 || r.reports != 1 {
+		t.Errorf("got pairs %v and %d reports, want two pairs and one report", r.pairs, r.reports)
+	}
+}
+
+func TestMultiplexerSendsEscalatedAlerts(t *testing.T) {
+	m, r := newRecorded(t, ChannelOptions{MinSeverity: types.SeverityInfo})
+
+	m.NotifyAlert(rugAlert(types.SeverityWarning))
+	m.NotifyAlert(rugAlert(types.SeverityCritical))
+	m.NotifyAlert(rugAlert(types.SeverityCritical))
+	m.Close()
+
+	want := []types.AlertSeverity{types.SeverityWarning, types.SeverityCritical}
+	if fmt.Sprint(r.alerts) != fmt.Sprint(want) {
+		t.Errorf("got alerts %v, want %v", r.alerts, want)
+	}
+}
+
+func TestMultiplexerKeepsRepeatsWithoutDedupWindow(t *testing.T) {
+	m, r := newRecorded(t, ChannelOptions{MinSeverity: types.SeverityInfo})
+	if err := m.SetPolicy(0, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	m.NotifyNewPair(pair(1))
+	m.NotifyNewPair(pair(1))
+	m.Close()
+
+	if len(r.pairs) != 2 {
+		t.Errorf("got pairs %v, want the repeat too", r.pairs)
+	}
+}
+
+func TestMultiplexerThrottlesAllButUrgent(t *testing.T) {
+	m, r := newRecorded(t, ChannelOptions{MinSeverity: types.SeverityInfo, MaxPerMinute: 2})
+
+	for i := 0; i < 5; i++ {
+		m.NotifyNewPair(pair(i))
+	}
+	m.NotifyAlert(rugAlert(types.SeverityCritical))
+	m.NotifyTrade(types.Trade{Side: types.TradeBuy, Mint: sampleMint, Amount: 0.5})
+	m.NotifyTrade(types.Trade{Side: types.TradeBuy, Mint: sampleMint, Amount: 0.5})
+	m.Close()
+
+	if len(r.pairs) != 2 {
+		t.Errorf("got %d new pairs, want 2 a minute", len(r.pairs))
+	}
+	if len(r.alerts) != 1 || r.trades != 2 {
+		t.Errorf("got %d alerts and %d trades, want every one", len(r.alerts), r.trades)
+	}
+}
+
+func TestMultiplexerBatchesDigests(t *testing.T) {
+	m, r := newRecorded(t, ChannelOptions{MinSeverity: types.SeverityInfo, Digest: true})
+	m.NotifyNewPair(pair(1))
+	m.NotifyNewPair(pair(2))
+	m.NotifySafetyReport(sampleToken())
+	m.NotifyAlert(rugAlert(types.SeverityInfo))
+	m.NotifyAlert(rugAlert(types.SeverityWarning))
+	m.NotifyTrade(types.Trade{Side: types.TradeSell, Mint: sampleMint, Amount: 1000})
+
+	// Close sends the pending digest
+	m.Close()
+
+	if len(r.pairs) != 0 || r.reports != 0 {
+		t.Errorf("sent %d pairs and %d reports straight away, want them in the digest", len(r.pairs), r.reports)
+	}
+	if len(r.alerts) != 1 || r.alerts[0] != types.SeverityWarning || r.trades != 1 {
+		t.Errorf("got alerts %v and %d trades, want the warning and the trade straight away", r.alerts, r.trades)
+	}
+	if len(r.digests) != 1 || len(r.digests[0].Entries) != 4 {
+		t.Fatalf("got digests %+v, want one of 4 entries", r.digests)
+	}
+	if kind := r.digests[0].Entries[0].Kind; kind != "new pair" {
+		t.Errorf("first digest entry is a %q, want a new pair", kind)
+	}
+}
+
+func TestMultiplexerSendsDigestWhenTurnedOff(t *testing.T) {
+	m, r := newRecorded(t, ChannelOptions{MinSeverity: types.SeverityInfo, Digest: true})
+	defer m.Close()
+
+	m.NotifyNewPair(pair(1))
+	if err := m.SetOptions("test", ChannelOptions{MinSeverity: types.SeverityInfo}); err != nil {
+		t.Fatal(err)
+	}
+	m.NotifyNewPair(pair(2))
+	m.Close()
+
+	if len(r.digests) != 1 || len(r.digests[0].Entries) != 1 || len(r.pairs) != 1 {
+		t.Errorf("got digests %+v and pairs %v, want the held pair digested and the next sent", r.digests, r.pairs)
+	}
+}
+
+func TestMultiplexerFiltersBySeverity(t *testing.T) {
+	m := NewMultiplexer()
+	everything, critical := &recorder{}, &recorder{}
+	if err := m.Add("everything", everything, ChannelOptions{MinSeverity: types.SeverityInfo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("critical", critical, ChannelOptions{MinSeverity: types.SeverityCritical}); err != nil {
+		t.Fatal(err)
+	}
+
+	m.NotifyNewPair(pair(1))
+	m.NotifySafetyReport(sampleToken())
+	m.NotifyAlert(rugAlert(types.SeverityInfo))
+	m.NotifyAlert(rugAlert(types.SeverityWarning))
+	m.NotifyAlert(rugAlert(types.SeverityCritical))
+	m.NotifyTrade(types.Trade{Side: types.TradeBuy, Mint: sampleMint, Amount: 0.5})
+	m.NotifyTrade(types.Trade{Side: types.TradeBuy, Mint: sampleMint, Amount: 0.5, Error: "insufficient balance"})
+	m.Close()
+
+	if len(everything.pairs) != 1 || everything.reports != 1 || len(everything.alerts) != 3 || everything.trades != 2 {
+		t.Errorf("info channel got %d pairs, %d reports, alerts %v and %d trades; want all of them",
+			len(everything.pairs), everything.reports, everything.alerts, everything.trades)
+	}
+	if len(critical.pairs) != 0 || critical.reports != 0 || fmt.Sprint(critical.alerts) != "[critical]" || critical.trades != 1 {
+		t.Errorf("critical channel got %d pairs, %d reports, alerts %v and %d trades; want the critical alert and the failed trade",
+			len(critical.pairs), critical.reports, critical.alerts, critical.trades)
+	}
+}
+
+// panicker is a channel whose backend blows up on every notification.
+type panicker struct{ recorder }
+
+func (p *panicker) NotifyNewPair(pair types.RaydiumPair) { panic("backend bug") }
+
+// stalled is a channel whose backend hangs until released.
+type stalled struct {
+	recorder
+	release chan struct{}
+}
+
+func (s *stalled) NotifyNewPair(pair types.RaydiumPair) {
+	<-s.release
+	s.recorder.NotifyNewPair(pair)
+}
+
+func (r *recorder) received() (pairs, trades int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pairs), r.trades
+}
+
+func TestMultiplexerRecoversFromPanics(t *testing.T) {
+	m := NewMultiplexer()
+	healthy, broken := &recorder{}, &panicker{}
+	if err := m.Add("healthy", healthy, ChannelOptions{MinSeverity: types.SeverityInfo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("broken", broken, ChannelOptions{MinSeverity: types.SeverityInfo}); err != nil {
+		t.Fatal(err)
+	}
+
+	m.NotifyNewPair(pair(1))
+	m.NotifyNewPair(pair(2))
+	m.NotifyTrade(types.Trade{Side: types.TradeSell, Mint: sampleMint, Amount: 1000})
+	m.Close()
+
+	if len(healthy.pairs) != 2 || healthy.trades != 1 {
+		t.Errorf("healthy channel got %d pairs and %d trades, want 2 and 1", len(healthy.pairs), healthy.trades)
+	}
+	if broken.trades != 1 {
+		t.Errorf("a panic on new pairs stopped the broken channel's trades")
+	}
+}
+
+func TestMultiplexerQueuesPerChannel(t *testing.T) {
+	m := NewMultiplexer()
+	if err := m.SetPolicy(0, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	healthy := &recorder{}
+	slow := &stalled{release: make(chan struct{})}
+	if err := m.Add("healthy", healthy, ChannelOptions{MinSeverity: types.SeverityInfo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("slow", slow, ChannelOptions{MinSeverity: types.SeverityInfo}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Twice what the slow channel's queue holds, each delivered to the
+	// healthy channel before the next; none of it may wait on the slow one
+	deadline := time.Now().Add(5 * time.Second)
+	await := func(pairs, trades int) {
+		for {
+			gotPairs, gotTrades := healthy.received()
+			if gotPairs >= pairs && gotTrades >= trades {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("healthy channel stuck at %d pairs and %d trades behind a stalled one", gotPairs, gotTrades)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	count := NOTIFY_QUEUE_SIZE * 2
+	for i := 0; i < count; i++ {
+		m.NotifyNewPair(pair(i))
+		await(i+1, 0)
+	}
+	m.NotifyTrade(types.Trade{Side: types.TradeSell, Mint: sampleMint, Amount: 1000})
+	await(count, 1)
+
+	close(slow.release)
+	m.Close()
+
+	// One in delivery plus a full queue; the rest, and the trade, were dropped
+	if pairs, trades := slow.received(); pairs < NOTIFY_QUEUE_SIZE || pairs > NOTIFY_QUEUE_SIZE+1 || trades != 0 {
+		t.Errorf("slow channel got %d pairs and %d trades, want its queue's worth and no trade", pairs, trades)
+	}
+}
@@ -0,0 +1,131 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+
+	"grind/types"
+)
+
+const (
+	DEFAULT_DEDUP_WINDOW    = 10 * time.Minute
+	DEFAULT_DIGEST_INTERVAL = 5 * time.Minute
+	DIGEST_MAX_ENTRIES      = 50 // Entries listed in one digest; the rest are counted
+)
+
+// ChannelOptions decide what a channel is sent and when.
+type ChannelOptions struct {
+	MinSeverity types.AlertSeverity
+	// MaxPerMinute caps low-priority notifications sent straight away; 0 is no cap
+	MaxPerMinute int
+	// Digest batches low-priority notifications into one message per digest interval
+	Digest bool
+}
+
+// Digest is the low-priority notifications batched for a channel over an interval.
+type Digest struct {
+	Since   time.Time
+	Until   time.Time
+	Entries []DigestEntry
+	Omitted int // Entries past DIGEST_MAX_ENTRIES
+}
+
+// DigestEntry is one notification in a digest, summed up in a line.
+type DigestEntry struct {
+	Time   time.Time
+	Kind   string
+	Mint   string
+	Symbol string
+	Text   string
+}
+
+// FormatDigest lists the entries one per line, as the default digest template
+// does, for plain text channels.
+func FormatDigest(digest Digest) string {
+	return mustRender(TEMPLATE_DIGEST, digest)
+}
+
+// bucket is a token bucket refilled at rate tokens a minute, up to rate.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) take(rate int, now time.Time) bool {
+	if rate <= 0 {
+		return true
+	}
+	if b.last.IsZero() {
+		b.tokens = float64(rate)
+	} else {
+		b.tokens = min(float64(rate), b.tokens+now.Sub(b.last).Minutes()*float64(rate))
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// notification is one Notify call on its way through the multiplexer.
+type notification struct {
+	kind     string
+	key      string // Repeats of a key within the dedup window are dropped; empty never is
+	severity types.AlertSeverity
+	urgent   bool // Skips throttling and digests
+	entry    DigestEntry
+	send     func(Notifier)
+}
+
+func newPairNotification(pair types.RaydiumPair) notification {
+	return notification{
+		kind:     "new pair",
+		key:      "pair:" + pair.Address,
+		severity: types.SeverityInfo,
+		entry: DigestEntry{Mint: pair.Address, Symbol: pair.Symbol,
+			Text: fmt.Sprintf("🚀 %s listed, liquidity %s", pair.Symbol, FormatUSD(pair.Liquidity))},
+		send: func(n Notifier) { n.NotifyNewPair(pair) },
+	}
+}
+
+func safetyReportNotification(token types.TokenReport) notification {
+	text := fmt.Sprintf("🛡 %s rejected: %s", token.Pair.Symbol, token.Report.Summary())
+	if token.Report.Passed() {
+		text = fmt.Sprintf("🔥 %s passed filters", token.Pair.Symbol)
+		if score, ok := token.Score(); ok {
+			text += fmt.Sprintf(", score %.1f", score.Total)
+		}
+	}
+	return notification{
+		kind:     "safety report",
+		key:      fmt.Sprintf("report:%s:%t", token.Pair.Address, token.Report.Passed()),
+		severity: types.SeverityInfo,
+		entry:    DigestEntry{Mint: token.Pair.Address, Symbol: token.Pair.Symbol, Text: text},
+		send:     func(n Notifier) { n.NotifySafetyReport(token) },
+	}
+}
+
+// alertNotification is urgent from warning up, so rug alerts go out at once.
+// The key holds the severity so an escalation is not taken for a repeat.
+func alertNotification(alert types.Alert) notification {
+	return notification{
+		kind:     "alert",
+		key:      fmt.Sprintf("alert:%s:%s:%s", alert.Kind, alert.Severity, alert.Mint),
+		severity: alert.Severity,
+		urgent:   alert.Severity.AtLeast(types.SeverityWarning),
+		entry: DigestEntry{Mint: alert.Mint, Symbol: alert.Symbol,
+			Text: fmt.Sprintf("%s %s: %s", alertIcons[alert.Severity], alert.Symbol, alert.Message)},
+		send: func(n Notifier) { n.NotifyAlert(alert) },
+	}
+}
+
+// tradeNotification is always urgent and never a repeat.
+func tradeNotification(trade types.Trade) notification {
+	return notification{
+		kind:     "trade",
+		severity: trade.Severity(),
+		urgent:   true,
+		send:     func(n Notifier) { n.NotifyTrade(trade) },
+	}
+}
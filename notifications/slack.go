@@ -0,0 +1,182 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+
+	"grind/logging"
+	"grind/types"
+)
+
+// Slack limits, see https://api.slack.com/reference/block-kit/blocks
+const (
+	SLACK_HEADER_LIMIT = 150
+	SLACK_TEXT_LIMIT   = 3000
+)
+
+var slackLog = logging.For("slack")
+
+// slackMessage carries Block Kit blocks, with text as the fallback shown in
+// notifications.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func slackHeader(text string) slackBlock {
+	return slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(text, SLACK_HEADER_LIMIT)}}
+}
+
+func slackSection(markdown string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncate(markdown, SLACK_TEXT_LIMIT)}}
+}
+
+// slackFields lays out name/value pairs two to a row.
+func slackFields(pairs ...string) slackBlock {
+	block := slackBlock{Type: "section"}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		block.Fields = append(block.Fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", pairs[i], pairs[i+1])})
+	}
+	return block
+}
+
+func slackLinks(links []Link) slackBlock {
+	parts := make([]string, 0, len(links))
+	for _, link := range links {
+		parts = append(parts, fmt.Sprintf("<%s|%s>", link.URL, link.Name))
+	}
+	return slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: strings.Join(parts, " · ")}}}
+}
+
+// SlackNotifier posts Block Kit messages to a Slack incoming webhook, or plain
+// mrkdwn for notifications it has a template for.
+type SlackNotifier struct {
+	templated
+	webhook *webhook
+}
+
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhook: newWebhook(webhookURL, slackLog)}
+}
+
+func (s *SlackNotifier) SetWebhookURL(webhookURL string) {
+	s.webhook.setURL(webhookURL)
+}
+
+func (s *SlackNotifier) NotifyNewPair(pair types.RaydiumPair) {
+	if message, ok := s.render(TEMPLATE_NEW_PAIR, pair); ok {
+		s.send(message)
+		return
+	}
+	title := fmt.Sprintf("🚀 New token: %s", pair.Symbol)
+	s.send(title,
+		slackHeader(title),
+		slackFields("Name", pair.Name, "Liquidity", FormatUSD(pair.Liquidity), "Market cap", FormatUSD(pair.MarketCap),
+			"Mint", "`"+pair.Address+"`"),
+		slackLinks(TokenLinks(pair.Address)),
+	)
+}
+
+func (s *SlackNotifier) NotifySafetyReport(token types.TokenReport) {
+	if message, ok := s.render(TEMPLATE_SAFETY_REPORT, &token); ok {
+		s.send(message)
+		return
+	}
+	pair, report := token.Pair, token.Report
+	title := fmt.Sprintf("🔥 %s passed filters", pair.Symbol)
+	if !report.Passed() {
+		title = fmt.Sprintf("🛡 %s rejected", pair.Symbol)
+	}
+
+	score := "n/a"
+	if breakdown, ok := token.Score(); ok {
+		score = fmt.Sprintf("%.1f (%s)", breakdown.Total, breakdown.Model)
+	}
+
+	s.send(title,
+		slackHeader(title),
+		slackFields("Liquidity", FormatUSD(pair.Liquidity), "Market cap", FormatUSD(pair.MarketCap),
+			"Holders", fmt.Sprint(token.Safety.HolderCount), "Score", score),
+		slackSection(truncate("```"+FormatCheckTable(report), SLACK_TEXT_LIMIT-len("```"))+"```"),
+		slackSection("`"+pair.Address+"`"),
+		slackLinks(TokenLinks(pair.Address)),
+	)
+}
+
+func (s *SlackNotifier) NotifyAlert(alert types.Alert) {
+	if message, ok := s.render(TEMPLATE_ALERT, alert); ok {
+		s.send(message)
+		return
+	}
+	title := fmt.Sprintf("%s %s: %s", alertIcons[alert.Severity], strings.ToUpper(string(alert.Severity)), alert.Symbol)
+	s.send(title,
+		slackHeader(title),
+		slackSection(alert.Message),
+		slackFields("Kind", alert.Kind, "Mint", "`"+alert.Mint+"`"),
+		slackLinks(TokenLinks(alert.Mint)),
+	)
+}
+
+func (s *SlackNotifier) NotifyTrade(trade types.Trade) {
+	if message, ok := s.render(TEMPLATE_TRADE, trade); ok {
+		s.send(message)
+		return
+	}
+	amount := fmt.Sprintf("%g %s", trade.Amount, trade.Symbol)
+	if trade.Side == types.TradeBuy {
+		amount = fmt.Sprintf("%g SOL", trade.Amount)
+	}
+
+	var title string
+	switch {
+	case trade.Error != "":
+		title = fmt.Sprintf("🚨 Failed to %s %s", trade.Side, trade.Symbol)
+	case trade.Side == types.TradeBuy:
+		title = fmt.Sprintf("🟢 Bought %s", trade.Symbol)
+	default:
+		title = fmt.Sprintf("🔴 Sold %s", trade.Symbol)
+	}
+
+	blocks := []slackBlock{slackHeader(title)}
+	if trade.Error != "" {
+		blocks = append(blocks, slackSection(trade.Error))
+	}
+	fields := []string{"Amount", amount}
+	if trade.Reason != "" {
+		fields = append(fields, "Reason", trade.Reason)
+	}
+	blocks = append(blocks, slackFields(fields...))
+
+	links := TokenLinks(trade.Mint)
+	if trade.Signature != "" {
+		links = append([]Link{TxLink(trade.Signature)}, links...)
+	}
+	s.send(title, append(blocks, slackLinks(links))...)
+}
+
+func (s *SlackNotifier) NotifyDigest(digest Digest) {
+	if message, ok := s.render(TEMPLATE_DIGEST, digest); ok {
+		s.send(message)
+		return
+	}
+	title, lines, _ := strings.Cut(FormatDigest(digest), "\n")
+	s.send(title, slackHeader(title), slackSection(lines))
+}
+
+func (s *SlackNotifier) send(text string, blocks ...slackBlock) {
+	if err := s.webhook.post(slackMessage{Text: text, Blocks: blocks}); err != nil {
+		slackLog.Error("Failed to send slack notification", logging.Err(err))
+	}
+}
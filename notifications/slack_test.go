@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"grind/types"
+)
+
+func decodeSlack(t *testing.T, body string) slackMessage {
+	t.Helper()
+	var message slackMessage
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	return message
+}
+
+// slackMarkdown joins the text of every block, for looking things up in.
+func slackMarkdown(message slackMessage) string {
+	var texts []string
+	for _, block := range message.Blocks {
+		if block.Text != nil {
+			texts = append(texts, block.Text.Text)
+		}
+		for _, text := range append(block.Fields, block.Elements...) {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func TestSlackSafetyReportBlocks(t *testing.T) {
+	stub := newWebhookStub(t)
+	NewSlackNotifier(stub.URL).NotifySafetyReport(sampleToken())
+
+	posts := stub.posts()
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	message := decodeSlack(t, posts[0])
+
+	if message.Text != "🔥 POPCAT passed filters" {
+		t.Errorf("fallback text = %q", message.Text)
+	}
+	if header := message.Blocks[0]; header.Type != "header" || header.Text.Type != "plain_text" {
+		t.Errorf("first block is %+v, want a plain text header", header)
+	}
+	markdown := slackMarkdown(message)
+	for _, want := range []string{
+		"*Liquidity*\n$48.3K",
+		"*Market cap*\n$1.25M",
+		"*Holders*\n412",
+		"*Score*\n72.5 (heuristic)",
+		"PASS  holder_count",
+		"<https://dexscreener.com/solana/" + sampleMint + "|DexScreener>",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("message is missing %q:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestSlackFailedTrade(t *testing.T) {
+	stub := newWebhookStub(t)
+	NewSlackNotifier(stub.URL).NotifyTrade(types.Trade{Side: types.TradeSell, Mint: sampleMint, Symbol: "POPCAT",
+		Amount: 1200, Reason: "emergency exit: liquidity pulled", Error: "insufficient funds"})
+
+	message := decodeSlack(t, stub.posts()[0])
+	if message.Text != "🚨 Failed to sell POPCAT" {
+		t.Errorf("fallback text = %q", message.Text)
+	}
+	markdown := slackMarkdown(message)
+	for _, want := range []string{"insufficient funds", "*Amount*\n1200 POPCAT", "*Reason*\nemergency exit: liquidity pulled"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("message is missing %q:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "solscan.io/tx/") {
+		t.Errorf("message links a transaction that was never sent:\n%s", markdown)
+	}
+}
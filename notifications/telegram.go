@@ -1,11 +1,80 @@
 package notifications
 
 import (
-	"log"
+	"errors"
+
+	"grind/logging"
+	"grind/types"
 )
 
+var telegramLog = logging.For("telegram")
+
+// SendMessage posts to the configured chat. Without a bot token it does nothing.
 func (t *TelegramNotifier) SendMessage(message string) error {
-	log.Printf("Sending telegram notification: %s", message)
-	// Implementation
+	return t.sendToChat(message, nil)
+}
+
+func (t *TelegramNotifier) sendToChat(message string, keyboard *inlineKeyboard) error {
+	t.mu.RLock()
+	chatID := t.chatID
+	t.mu.RUnlock()
+
+	telegramLog.Debug("Sending telegram notification", "chat", chatID, "message", message)
+	if err := t.send(chatID, message, keyboard); err != nil && !errors.Is(err, errTelegramNotConfigured) {
+		return err
+	}
 	return nil
 }
+
+// message renders the configured template by name, or the default one.
+func (t *TelegramNotifier) message(name string, data any) string {
+	if message, ok := t.render(name, data); ok {
+		return message
+	}
+	return mustRender(name, data)
+}
+
+func (t *TelegramNotifier) NotifyNewPair(pair types.RaydiumPair) {
+	if err := t.SendMessage(t.message(TEMPLATE_NEW_PAIR, pair)); err != nil {
+		telegramLog.Error("Failed to send telegram notification", logging.Err(err))
+	}
+}
+
+func (t *TelegramNotifier) NotifySafetyReport(token types.TokenReport) {
+	var keyboard *inlineKeyboard
+	if token.Report.Passed() {
+		keyboard = t.buyButtons(token.Pair.Address)
+	}
+	if err := t.sendToChat(t.message(TEMPLATE_SAFETY_REPORT, &token), keyboard); err != nil {
+		telegramLog.Error("Failed to send telegram notification", logging.Err(err))
+	}
+}
+
+var alertIcons = map[types.AlertSeverity]string{
+	types.SeverityInfo:     "ℹ️",
+	types.SeverityWarning:  "⚠️",
+	types.SeverityCritical: "🚨",
+}
+
+func (t *TelegramNotifier) NotifyAlert(alert types.Alert) {
+	if err := t.SendMessage(t.message(TEMPLATE_ALERT, alert)); err != nil {
+		telegramLog.Error("Failed to send telegram notification", logging.Err(err))
+	}
+}
+
+func (t *TelegramNotifier) NotifyTrade(trade types.Trade) {
+	if err := t.SendMessage(t.message(TEMPLATE_TRADE, trade)); err != nil {
+		telegramLog.Error("Failed to send telegram notification", logging.Err(err))
+	}
+}
+
+// formatTrade is the default trade message, for replies to bot commands.
+func formatTrade(trade types.Trade) string {
+	return mustRender(TEMPLATE_TRADE, trade)
+}
+
+func (t *TelegramNotifier) NotifyDigest(digest Digest) {
+	if err := t.SendMessage(t.message(TEMPLATE_DIGEST, digest)); err != nil {
+		telegramLog.Error("Failed to send telegram notification", logging.Err(err))
+	}
+}
@@ -0,0 +1,141 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	TELEGRAM_API_URL = "https://api.telegram.org"
+	TELEGRAM_TIMEOUT = 10 * time.Second
+	// Telegram rejects longer messages and callback data
+	TELEGRAM_MESSAGE_LIMIT  = 4096
+	TELEGRAM_CALLBACK_LIMIT = 64
+)
+
+var errTelegramNotConfigured = errors.New("telegram bot token not set")
+
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+	Parameters  *struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+type telegramUpdate struct {
+	UpdateID      int64             `json:"update_id"`
+	Message       *telegramMessage  `json:"message"`
+	CallbackQuery *telegramCallback `json:"callback_query"`
+}
+
+type telegramMessage struct {
+	MessageID int64         `json:"message_id"`
+	Chat      telegramChat  `json:"chat"`
+	From      *telegramUser `json:"from"`
+	Text      string        `json:"text"`
+}
+
+type telegramChat struct {
+	ID int64 `json:"id"`
+}
+
+type telegramUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type telegramCallback struct {
+	ID      string           `json:"id"`
+	From    telegramUser     `json:"from"`
+	Message *telegramMessage `json:"message"`
+	Data    string           `json:"data"`
+}
+
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// call invokes a Bot API method, waiting out 429s like the webhooks do.
+func (t *TelegramNotifier) call(ctx context.Context, method string, params, result any) error {
+	t.mu.RLock()
+	botKey, baseURL := t.botKey, t.baseURL
+	t.mu.RUnlock()
+	if botKey == "" {
+		return errTelegramNotConfigured
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram %s: %w", method, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/bot%s/%s", baseURL, botKey, method), bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to build telegram %s: %w", method, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			// The URL holds the bot token, so it is left out of the error
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return fmt.Errorf("failed to call telegram %s: %w", method, err)
+		}
+		var response telegramResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode telegram %s: %w", method, err)
+		}
+
+		if response.ErrorCode == http.StatusTooManyRequests && response.Parameters != nil && attempt < WEBHOOK_MAX_RETRIES {
+			wait := min(time.Duration(response.Parameters.RetryAfter)*time.Second, WEBHOOK_MAX_WAIT)
+			telegramLog.Warn("Telegram rate limited, retrying", "method", method, "wait", wait)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		if !response.OK {
+			return fmt.Errorf("telegram %s failed: %d %s", method, response.ErrorCode, response.Description)
+		}
+		if result != nil {
+			if err := json.Unmarshal(response.Result, result); err != nil {
+				return fmt.Errorf("failed to decode telegram %s result: %w", method, err)
+			}
+		}
+		return nil
+	}
+}
+
+// send posts text to chatID, with buttons if keyboard is set.
+func (t *TelegramNotifier) send(chatID, text string, keyboard *inlineKeyboard) error {
+	ctx, cancel := context.WithTimeout(context.Background(), TELEGRAM_TIMEOUT)
+	defer cancel()
+
+	params := map[string]any{"chat_id": chatID, "text": truncate(text, TELEGRAM_MESSAGE_LIMIT), "disable_web_page_preview": true}
+	if keyboard != nil {
+		params["reply_markup"] = keyboard
+	}
+	return t.call(ctx, "sendMessage", params, nil)
+}
@@ -0,0 +1,407 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"grind/logging"
+	"grind/types"
+)
+
+const (
+	TELEGRAM_POLL_SECONDS = 30 // getUpdates long-poll timeout
+	TELEGRAM_RETRY_DELAY  = 5 * time.Second
+	MAX_MINT_LENGTH       = 44 // Base58 of 32 bytes
+	// CONFIRM_BUTTON_TIMEOUT is how long a trade's confirm button can be tapped
+	CONFIRM_BUTTON_TIMEOUT = 2 * time.Minute
+)
+
+// Callback data of inline buttons. The buy buttons on alerts only ask for
+// confirmation; trades go out from the confirmation buttons, which carry the
+// unix second they were issued in base 36.
+const (
+	CALLBACK_BUY     = "buy"    // buy:<mint>:<sol>
+	CALLBACK_CONFIRM = "do"     // do:buy:<mint>:<sol>:<issued> or do:sell:<mint>:<percent>:<issued>
+	CALLBACK_CANCEL  = "cancel" // cancel
+)
+
+const BOT_HELP = `/status - scanner state, tracked tokens and positions
+/positions - open positions
+/check <mint> - run the safety checks on a token
+/buy <mint> <sol> - buy a token, after confirming
+/sell <mint> <percent> - sell part of a position, after confirming
+/pause - stop evaluating new tokens
+/resume - start evaluating new tokens again`
+
+var botLog = logging.For("telegram-bot")
+
+// BotStatus is what /status reports.
+type BotStatus struct {
+	Paused    bool
+	Tracked   int
+	Positions int
+	Since     time.Time
+}
+
+// Controller is what the bot's commands act on.
+type Controller interface {
+	Status() BotStatus
+	Positions() []types.Position
+	Check(mint string) (types.TokenReport, error)
+	Buy(mint string, sol float64) (types.Trade, error)
+	Sell(mint string, percent float64) (types.Trade, error)
+	SetPaused(paused bool)
+}
+
+// TelegramBot long-polls for commands and button presses, taking them only
+// from whitelisted chats. It replies through the notifier's bot.
+type TelegramBot struct {
+	notifier *TelegramNotifier
+	control  Controller
+	allowed  map[int64]bool
+}
+
+func NewTelegramBot(notifier *TelegramNotifier, control Controller, allowedChatIDs []int64) *TelegramBot {
+	allowed := make(map[int64]bool, len(allowedChatIDs))
+	for _, id := range allowedChatIDs {
+		allowed[id] = true
+	}
+	return &TelegramBot{notifier: notifier, control: control, allowed: allowed}
+}
+
+// Run handles updates until ctx is cancelled. Each update is handled on its
+// own goroutine, as trades wait for confirmation.
+func (b *TelegramBot) Run(ctx context.Context) {
+	botLog.Info("Taking telegram commands", "chats", len(b.allowed))
+	var offset int64
+	for {
+		updates, err := b.getUpdates(ctx, offset)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			botLog.Warn("Failed to get telegram updates", logging.Err(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(TELEGRAM_RETRY_DELAY):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			go b.handle(update)
+		}
+	}
+}
+
+func (b *TelegramBot) getUpdates(ctx context.Context, offset int64) ([]telegramUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, TELEGRAM_POLL_SECONDS*time.Second+TELEGRAM_TIMEOUT)
+	defer cancel()
+
+	var updates []telegramUpdate
+	err := b.notifier.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         TELEGRAM_POLL_SECONDS,
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+func (b *TelegramBot) handle(update telegramUpdate) {
+	defer func() {
+		if r := recover(); r != nil {
+			botLog.Error("Telegram update handler panicked", "update", update.UpdateID, "panic", r)
+		}
+	}()
+
+	switch {
+	case update.Message != nil && strings.HasPrefix(update.Message.Text, "/"):
+		message := update.Message
+		if !b.allow(message.Chat.ID, message.From) {
+			return
+		}
+		botLog.Info("Telegram command", "chat", message.Chat.ID, "command", message.Text)
+		text, keyboard := b.command(message.Text)
+		b.reply(message.Chat.ID, text, keyboard)
+
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		callback := update.CallbackQuery
+		if !b.allow(callback.Message.Chat.ID, &callback.From) {
+			b.answer(callback.ID, "Not allowed")
+			return
+		}
+		botLog.Info("Telegram button", "chat", callback.Message.Chat.ID, "data", callback.Data)
+		b.button(callback)
+	}
+}
+
+func (b *TelegramBot) allow(chatID int64, from *telegramUser) bool {
+	if b.allowed[chatID] {
+		return true
+	}
+	user := ""
+	if from != nil {
+		user = from.Username
+	}
+	botLog.Warn("Ignored telegram update from a chat not allowed", "chat", chatID, "user", user)
+	return false
+}
+
+// command runs a slash command and returns the reply.
+func (b *TelegramBot) command(text string) (string, *inlineKeyboard) {
+	fields := strings.Fields(text)
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	// In groups commands come as /status@botname
+	if at := strings.IndexByte(name, '@'); at > 0 {
+		name = name[:at]
+	}
+
+	switch name {
+	case "/start", "/help":
+		return BOT_HELP, nil
+
+	case "/status":
+		return formatBotStatus(b.control.Status()), nil
+
+	case "/positions":
+		return formatPositions(b.control.Positions()), nil
+
+	case "/check":
+		if len(args) != 1 {
+			return "Usage: /check <mint>", nil
+		}
+		token, err := b.control.Check(args[0])
+		if err != nil {
+			return fmt.Sprintf("❌ Check failed: %v", err), nil
+		}
+		var keyboard *inlineKeyboard
+		if token.Report.Passed() {
+			keyboard = b.notifier.buyButtons(token.Report.Mint)
+		}
+		return formatTokenReport(token), keyboard
+
+	case "/buy":
+		sol, err := parseAmount(args, 2, 1)
+		if err != nil {
+			return "Usage: /buy <mint> <sol>", nil
+		}
+		if refusal, over := b.overBuyLimit(sol); over {
+			return refusal, nil
+		}
+		return confirmation(types.TradeBuy, args[0], sol, time.Now())
+
+	case "/sell":
+		if len(args) == 2 {
+			args[1] = strings.TrimSuffix(args[1], "%")
+		}
+		percent, err := parseAmount(args, 2, 1)
+		if err != nil || percent > 100 {
+			return "Usage: /sell <mint> <percent>, with percent from 1 to 100", nil
+		}
+		return confirmation(types.TradeSell, args[0], percent, time.Now())
+
+	case "/pause":
+		b.control.SetPaused(true)
+		return "⏸ Paused. New tokens are not evaluated; open positions are still watched.", nil
+
+	case "/resume":
+		b.control.SetPaused(false)
+		return "▶️ Resumed scanning.", nil
+	}
+	return "Unknown command. /help lists them.", nil
+}
+
+// button handles an inline button press.
+func (b *TelegramBot) button(callback *telegramCallback) {
+	chatID := callback.Message.Chat.ID
+	parts := strings.Split(callback.Data, ":")
+
+	switch {
+	case parts[0] == CALLBACK_BUY && len(parts) == 3:
+		sol, err := parseAmount(parts[1:], 2, 1)
+		if err != nil {
+			b.answer(callback.ID, "Bad button")
+			return
+		}
+		b.answer(callback.ID, "")
+		if refusal, over := b.overBuyLimit(sol); over {
+			b.reply(chatID, refusal, nil)
+			return
+		}
+		text, keyboard := confirmation(types.TradeBuy, parts[1], sol, time.Now())
+		b.reply(chatID, text, keyboard)
+
+	case parts[0] == CALLBACK_CONFIRM && len(parts) == 5 &&
+		(parts[1] == string(types.TradeBuy) || parts[1] == string(types.TradeSell)):
+		amount, err := parseAmount(parts[2:4], 2, 1)
+		issued, issuedErr := strconv.ParseInt(parts[4], 36, 64)
+		if err != nil || issuedErr != nil {
+			b.answer(callback.ID, "Bad button")
+			return
+		}
+		// Taking the buttons away first means a second tap cannot trade twice
+		if err := b.clearButtons(chatID, callback.Message.MessageID); err != nil {
+			botLog.Warn("Not trading, buttons already cleared", logging.Err(err))
+			b.answer(callback.ID, "Already handled")
+			return
+		}
+		if age := time.Since(time.Unix(issued, 0)); age > CONFIRM_BUTTON_TIMEOUT || age < -time.Minute {
+			b.answer(callback.ID, "Expired")
+			b.reply(chatID, fmt.Sprintf("⌛ That confirmation expired after %v. Ask again to trade.", CONFIRM_BUTTON_TIMEOUT), nil)
+			return
+		}
+		if types.TradeSide(parts[1]) == types.TradeBuy {
+			// The limit may have been lowered since the prompt
+			if refusal, over := b.overBuyLimit(amount); over {
+				b.answer(callback.ID, "Over the limit")
+				b.reply(chatID, refusal, nil)
+				return
+			}
+		}
+		b.answer(callback.ID, "Sending…")
+
+		var trade types.Trade
+		if types.TradeSide(parts[1]) == types.TradeBuy {
+			trade, err = b.control.Buy(parts[2], amount)
+		} else {
+			trade, err = b.control.Sell(parts[2], amount)
+		}
+		if err != nil && trade.Error == "" {
+			trade.Side, trade.Mint, trade.Error = types.TradeSide(parts[1]), parts[2], err.Error()
+		}
+		b.reply(chatID, formatTrade(trade), nil)
+
+	case parts[0] == CALLBACK_CANCEL:
+		b.clearButtons(chatID, callback.Message.MessageID)
+		b.answer(callback.ID, "Cancelled")
+
+	default:
+		b.answer(callback.ID, "Unknown button")
+	}
+}
+
+func (b *TelegramBot) reply(chatID int64, text string, keyboard *inlineKeyboard) {
+	if err := b.notifier.send(strconv.FormatInt(chatID, 10), text, keyboard); err != nil {
+		botLog.Error("Failed to send telegram reply", "chat", chatID, logging.Err(err))
+	}
+}
+
+func (b *TelegramBot) answer(callbackID, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), TELEGRAM_TIMEOUT)
+	defer cancel()
+	params := map[string]any{"callback_query_id": callbackID, "text": text}
+	if err := b.notifier.call(ctx, "answerCallbackQuery", params, nil); err != nil {
+		botLog.Warn("Failed to answer telegram button", logging.Err(err))
+	}
+}
+
+func (b *TelegramBot) clearButtons(chatID, messageID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), TELEGRAM_TIMEOUT)
+	defer cancel()
+	return b.notifier.call(ctx, "editMessageReplyMarkup", map[string]any{
+		"chat_id":      chatID,
+		"message_id":   messageID,
+		"reply_markup": inlineKeyboard{InlineKeyboard: [][]inlineButton{}},
+	}, nil)
+}
+
+// buyButtons offers the preset buy sizes for mint, or nothing without presets.
+func (t *TelegramNotifier) buyButtons(mint string) *inlineKeyboard {
+	t.mu.RLock()
+	presets := t.buyPresets
+	t.mu.RUnlock()
+
+	row := make([]inlineButton, 0, len(presets))
+	for _, sol := range presets {
+		data := fmt.Sprintf("%s:%s:%g", CALLBACK_BUY, mint, sol)
+		if len(data) > TELEGRAM_CALLBACK_LIMIT {
+			return nil
+		}
+		row = append(row, inlineButton{Text: fmt.Sprintf("Buy %g SOL", sol), CallbackData: data})
+	}
+	if len(row) == 0 {
+		return nil
+	}
+	return &inlineKeyboard{InlineKeyboard: [][]inlineButton{row}}
+}
+
+// overBuyLimit refuses a buy of more SOL than the bot may spend at once.
+func (b *TelegramBot) overBuyLimit(sol float64) (string, bool) {
+	limit := b.notifier.maxBuyAmount()
+	if sol <= limit {
+		return "", false
+	}
+	return fmt.Sprintf("❌ %g SOL is over the %g SOL limit for buys from Telegram.", sol, limit), true
+}
+
+// confirmation asks before a trade. amount is SOL for buys and a percentage of
+// the position for sells. The confirm button expires CONFIRM_BUTTON_TIMEOUT
+// after issued.
+func confirmation(side types.TradeSide, mint string, amount float64, issued time.Time) (string, *inlineKeyboard) {
+	if len(mint) > MAX_MINT_LENGTH || strings.Contains(mint, ":") {
+		return "That does not look like a mint address.", nil
+	}
+	data := fmt.Sprintf("%s:%s:%s:%g:%s", CALLBACK_CONFIRM, side, mint, amount, strconv.FormatInt(issued.Unix(), 36))
+	if len(data) > TELEGRAM_CALLBACK_LIMIT {
+		return fmt.Sprintf("%g has too many digits; round it and ask again.", amount), nil
+	}
+
+	text := fmt.Sprintf("Buy %s with %g SOL?", mint, amount)
+	if side == types.TradeSell {
+		text = fmt.Sprintf("Sell %g%% of the %s position?", amount, mint)
+	}
+	return text, &inlineKeyboard{InlineKeyboard: [][]inlineButton{{
+		{Text: "✅ Confirm", CallbackData: data},
+		{Text: "Cancel", CallbackData: CALLBACK_CANCEL},
+	}}}
+}
+
+// parseAmount reads the positive number at args[index] of want args.
+func parseAmount(args []string, want, index int) (float64, error) {
+	if len(args) != want {
+		return 0, fmt.Errorf("want %d arguments", want)
+	}
+	amount, err := strconv.ParseFloat(args[index], 64)
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("invalid amount %q", args[index])
+	}
+	return amount, nil
+}
+
+func formatBotStatus(status BotStatus) string {
+	state := "▶️ Scanning"
+	if status.Paused {
+		state = "⏸ Paused"
+	}
+	return fmt.Sprintf("%s\nTracked tokens: %d\nOpen positions: %d\nRunning for %s",
+		state, status.Tracked, status.Positions, time.Since(status.Since).Round(time.Minute))
+}
+
+func formatPositions(positions []types.Position) string {
+	if len(positions) == 0 {
+		return "No open positions."
+	}
+	var b strings.Builder
+	for _, position := range positions {
+		fmt.Fprintf(&b, "%s (%s)\n  %g at $%.8f, opened %s ago\n", position.Symbol, position.Mint,
+			position.Amount, position.EntryPrice, time.Since(position.OpenedAt).Round(time.Minute))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func formatTokenReport(token types.TokenReport) string {
+	verdict := "passed"
+	if !token.Report.Passed() {
+		verdict = "rejected"
+	}
+	message := fmt.Sprintf("🛡 %s (%s): %s\n%s", token.Report.Symbol, token.Report.Mint, verdict, FormatSafetyReport(token.Report))
+	if score, ok := token.Score(); ok {
+		message += fmt.Sprintf("\nScore: %.1f (%s)", score.Total, score.Model)
+	}
+	return message
+}
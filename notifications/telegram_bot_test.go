@@ -0,0 +1,233 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+const allowedChat = 1001
+
+// telegramStub answers Bot API calls and records them by method. A message's
+// buttons can only be cleared once, as with Telegram.
+type telegramStub struct {
+	mu      sync.Mutex
+	calls   map[string][]map[string]any
+	cleared map[float64]bool
+}
+
+func newTelegramStub(t *testing.T) (*telegramStub, *TelegramNotifier) {
+	t.Helper()
+	stub := &telegramStub{calls: make(map[string][]map[string]any), cleared: make(map[float64]bool)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/bottoken/") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		method := strings.TrimPrefix(r.URL.Path, "/bottoken/")
+		body, _ := io.ReadAll(r.Body)
+		var params map[string]any
+		json.Unmarshal(body, &params)
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		stub.calls[method] = append(stub.calls[method], params)
+		if method == "editMessageReplyMarkup" {
+			id := params["message_id"].(float64)
+			if stub.cleared[id] {
+				io.WriteString(w, `{"ok": false, "error_code": 400, "description": "Bad Request: message is not modified"}`)
+				return
+			}
+			stub.cleared[id] = true
+		}
+		io.WriteString(w, `{"ok": true, "result": true}`)
+	}))
+	t.Cleanup(server.Close)
+
+	notifier := NewTelegramNotifier("token", "1001")
+	notifier.SetBaseURL(server.URL)
+	notifier.SetBuyPresets([]float64{0.1, 0.5})
+	notifier.SetMaxBuy(1)
+	return stub, notifier
+}
+
+func (s *telegramStub) sent(method string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// fakeControl records what the bot asked of it.
+type fakeControl struct {
+	mu     sync.Mutex
+	paused bool
+	buys   []float64
+}
+
+func (c *fakeControl) Status() BotStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return BotStatus{Paused: c.paused, Tracked: 7, Positions: 2, Since: time.Now().Add(-time.Hour)}
+}
+
+func (c *fakeControl) Positions() []types.Position { return nil }
+
+func (c *fakeControl) Check(mint string) (types.TokenReport, error) {
+	return sampleToken(), nil
+}
+
+func (c *fakeControl) Buy(mint string, sol float64) (types.Trade, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buys = append(c.buys, sol)
+	return types.Trade{Side: types.TradeBuy, Mint: mint, Symbol: "POPCAT", Amount: sol, Signature: "5sig"}, nil
+}
+
+func (c *fakeControl) Sell(mint string, percent float64) (types.Trade, error) {
+	return types.Trade{}, nil
+}
+
+func (c *fakeControl) SetPaused(paused bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = paused
+}
+
+func command(chat int64, text string) telegramUpdate {
+	return telegramUpdate{Message: &telegramMessage{MessageID: 1, Chat: telegramChat{ID: chat}, Text: text}}
+}
+
+func press(chat, messageID int64, data string) telegramUpdate {
+	return telegramUpdate{CallbackQuery: &telegramCallback{ID: "cb", Data: data,
+		Message: &telegramMessage{MessageID: messageID, Chat: telegramChat{ID: chat}}}}
+}
+
+// confirmBuy is the data of the confirm button for a buy of sol issued at issued.
+func confirmBuy(sol string, issued time.Time) string {
+	return "do:buy:" + sampleMint + ":" + sol + ":" + strconv.FormatInt(issued.Unix(), 36)
+}
+
+func TestBotIgnoresChatsNotAllowed(t *testing.T) {
+	stub, notifier := newTelegramStub(t)
+	control := &fakeControl{}
+	bot := NewTelegramBot(notifier, control, []int64{allowedChat})
+
+	bot.handle(command(666, "/pause"))
+	bot.handle(press(666, 5, confirmBuy("0.5", time.Now())))
+
+	if control.Status().Paused || len(control.buys) > 0 {
+		t.Errorf("a chat not allowed paused the scanner or traded")
+	}
+	if replies := stub.sent("sendMessage"); len(replies) > 0 {
+		t.Errorf("replied to a chat not allowed: %v", replies)
+	}
+}
+
+func TestBotStatusAndPause(t *testing.T) {
+	stub, notifier := newTelegramStub(t)
+	control := &fakeControl{}
+	bot := NewTelegramBot(notifier, control, []int64{allowedChat})
+
+	bot.handle(command(allowedChat, "/pause@grind_bot"))
+	bot.handle(command(allowedChat, "/status"))
+
+	replies := stub.sent("sendMessage")
+	if len(replies) != 2 {
+		t.Fatalf("got %d replies, want 2", len(replies))
+	}
+	status := replies[1]["text"].(string)
+	if !strings.Contains(status, "Paused") || !strings.Contains(status, "Tracked tokens: 7") {
+		t.Errorf("status reply %q", status)
+	}
+	if replies[1]["chat_id"] != "1001" {
+		t.Errorf("replied to chat %v, want 1001", replies[1]["chat_id"])
+	}
+}
+
+func TestBotCheckOffersBuyButtons(t *testing.T) {
+	stub, notifier := newTelegramStub(t)
+	bot := NewTelegramBot(notifier, &fakeControl{}, []int64{allowedChat})
+
+	bot.handle(command(allowedChat, "/check "+sampleMint))
+
+	reply := stub.sent("sendMessage")[0]
+	markup, _ := json.Marshal(reply["reply_markup"])
+	if !strings.Contains(string(markup), `"callback_data":"buy:`+sampleMint+`:0.5"`) {
+		t.Errorf("reply markup %s has no 0.5 SOL buy button", markup)
+	}
+}
+
+func TestBotTradesOnceOnConfirm(t *testing.T) {
+	stub, notifier := newTelegramStub(t)
+	control := &fakeControl{}
+	bot := NewTelegramBot(notifier, control, []int64{allowedChat})
+
+	// The alert's button asks first
+	bot.handle(press(allowedChat, 4, "buy:"+sampleMint+":0.5"))
+	if len(control.buys) > 0 {
+		t.Fatalf("bought before confirming")
+	}
+	prompt := stub.sent("sendMessage")[0]
+	markup, _ := json.Marshal(prompt["reply_markup"])
+	if !strings.Contains(string(markup), `"callback_data":"do:buy:`+sampleMint+`:0.5:`) {
+		t.Fatalf("prompt markup %s has no confirm button", markup)
+	}
+
+	// Tapping confirm twice trades once
+	bot.handle(press(allowedChat, 5, confirmBuy("0.5", time.Now())))
+	bot.handle(press(allowedChat, 5, confirmBuy("0.5", time.Now())))
+	if len(control.buys) != 1 || control.buys[0] != 0.5 {
+		t.Fatalf("got buys %v, want one of 0.5 SOL", control.buys)
+	}
+	result := stub.sent("sendMessage")[1]["text"].(string)
+	if !strings.Contains(result, "Bought 0.5 SOL of POPCAT") {
+		t.Errorf("trade reply %q", result)
+	}
+}
+
+func TestBotRejectsStaleConfirmations(t *testing.T) {
+	stub, notifier := newTelegramStub(t)
+	control := &fakeControl{}
+	bot := NewTelegramBot(notifier, control, []int64{allowedChat})
+
+	bot.handle(press(allowedChat, 5, confirmBuy("0.5", time.Now().Add(-CONFIRM_BUTTON_TIMEOUT-time.Second))))
+	if len(control.buys) > 0 {
+		t.Fatalf("traded on an expired confirmation")
+	}
+	if replies := stub.sent("sendMessage"); len(replies) != 1 || !strings.Contains(replies[0]["text"].(string), "expired") {
+		t.Errorf("replies %v, want one saying the confirmation expired", replies)
+	}
+
+	// The old four-part data is refused too
+	bot.handle(press(allowedChat, 6, "do:buy:"+sampleMint+":0.5"))
+	if len(control.buys) > 0 {
+		t.Errorf("traded on a confirmation without a timestamp")
+	}
+}
+
+func TestBotCapsBuys(t *testing.T) {
+	stub, notifier := newTelegramStub(t)
+	control := &fakeControl{}
+	bot := NewTelegramBot(notifier, control, []int64{allowedChat})
+
+	bot.handle(command(allowedChat, "/buy "+sampleMint+" 5"))
+	reply := stub.sent("sendMessage")[0]
+	if reply["reply_markup"] != nil || !strings.Contains(reply["text"].(string), "over the 1 SOL limit") {
+		t.Errorf("a buy over the limit was offered: %v", reply)
+	}
+
+	// Lowering the limit also stops confirmations issued before it
+	bot.handle(command(allowedChat, "/buy "+sampleMint+" 0.5"))
+	notifier.SetMaxBuy(0.25)
+	bot.handle(press(allowedChat, 5, confirmBuy("0.5", time.Now())))
+	if len(control.buys) > 0 {
+		t.Errorf("bought %v over the lowered limit", control.buys)
+	}
+}
@@ -0,0 +1,266 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"grind/logging"
+	"grind/types"
+)
+
+// Template names, one per kind of notification.
+const (
+	TEMPLATE_NEW_PAIR      = "newPair"
+	TEMPLATE_SAFETY_REPORT = "safetyReport"
+	TEMPLATE_ALERT         = "alert"
+	TEMPLATE_TRADE         = "trade"
+	TEMPLATE_DIGEST        = "digest"
+)
+
+// templateData says what each template is rendered with. Reports go by
+// pointer so templates can call .Report.Passed and the like.
+var templateData = map[string]string{
+	TEMPLATE_NEW_PAIR:      "types.RaydiumPair",
+	TEMPLATE_SAFETY_REPORT: "*types.TokenReport",
+	TEMPLATE_ALERT:         "types.Alert",
+	TEMPLATE_TRADE:         "types.Trade",
+	TEMPLATE_DIGEST:        "notifications.Digest",
+}
+
+// TemplateNames lists the templates in the order notifications happen.
+func TemplateNames() []string {
+	return []string{TEMPLATE_NEW_PAIR, TEMPLATE_SAFETY_REPORT, TEMPLATE_ALERT, TEMPLATE_TRADE, TEMPLATE_DIGEST}
+}
+
+// defaultSources are the plain text messages Telegram sends, and the bot's
+// replies and plain text digests, where a config does not override them.
+var defaultSources = map[string]string{
+	TEMPLATE_NEW_PAIR: `🚀 New token found: {{.Name}} ({{.Address}})
+Liquidity: ${{printf "%.2f" .Liquidity}}`,
+
+	TEMPLATE_SAFETY_REPORT: `🛡 Safety report for {{.Pair.Symbol}} ({{.Pair.Address}}): {{if .Report.Passed}}passed{{else}}rejected{{end}}
+{{checks .Report}}`,
+
+	TEMPLATE_ALERT: `{{icon .Severity}} {{upper .Severity}}: {{.Symbol}} ({{.Mint}})
+{{.Message}}`,
+
+	TEMPLATE_TRADE: `{{$what := printf "%g %s (%s)" .Amount .Symbol .Mint -}}
+{{if eq .Side "buy"}}{{$what = printf "%g SOL of %s (%s)" .Amount .Symbol .Mint}}{{end -}}
+{{if .Error}}🚨 Failed to {{.Side}} {{$what}}: {{.Error}}
+{{- else if eq .Side "buy"}}🟢 Bought {{$what}}
+{{- else}}🔴 Sold {{$what}}{{end}}
+{{- if .Reason}}
+Reason: {{.Reason}}{{end}}
+{{- if .Signature}}
+Tx: {{tx .Signature}}{{end}}`,
+
+	TEMPLATE_DIGEST: `📋 {{add (len .Entries) .Omitted}} notifications since {{.Since.Format "15:04"}}
+{{range .Entries}}{{.Time.Format "15:04"}} {{.Text}}
+{{end}}{{if .Omitted}}…and {{.Omitted}} more{{end}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"usd":           FormatUSD,
+	"percent":       func(fraction float64) string { return fmt.Sprintf("%.1f%%", fraction*100) },
+	"signedPercent": func(fraction float64) string { return fmt.Sprintf("%+.1f%%", fraction*100) },
+	"duration":      FormatDuration,
+	"since":         func(t time.Time) string { return FormatDuration(time.Since(t)) },
+	"solscan":       func(mint string) string { return SOLSCAN_TOKEN_URL + mint },
+	"birdeye":       func(mint string) string { return BIRDEYE_TOKEN_URL + mint + "?chain=solana" },
+	"dexscreener":   func(mint string) string { return DEXSCREENER_TOKEN_URL + mint },
+	"tx":            func(signature string) string { return SOLSCAN_TX_URL + signature },
+	"links":         TokenLinks,
+	"checks":        FormatSafetyReport,
+	"checkTable":    FormatCheckTable,
+	"icon":          func(severity types.AlertSeverity) string { return alertIcons[severity] },
+	"upper":         func(v any) string { return strings.ToUpper(fmt.Sprint(v)) },
+	"truncate":      func(limit int, s string) string { return truncate(s, limit) },
+	"add":           func(a, b int) int { return a + b },
+}
+
+// FormatDuration keeps the two largest units, e.g. 3d4h, 2h15m, 45s.
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	switch {
+	case d >= 24*time.Hour:
+		d = d.Round(time.Hour)
+		return fmt.Sprintf("%s%dd%dh", sign, d/(24*time.Hour), d%(24*time.Hour)/time.Hour)
+	case d >= time.Hour:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%s%dh%dm", sign, d/time.Hour, d%time.Hour/time.Minute)
+	case d >= time.Minute:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%s%dm%ds", sign, d/time.Minute, d%time.Minute/time.Second)
+	default:
+		return sign + d.Round(time.Second).String()
+	}
+}
+
+// Templates are message templates by name. A name without one keeps the
+// channel's built-in message.
+type Templates struct {
+	set map[string]*template.Template
+}
+
+// ParseTemplates parses the non-empty sources by template name, and renders
+// each against sample data so a misspelt field fails here rather than when a
+// notification is sent.
+func ParseTemplates(sources map[string]string) (*Templates, error) {
+	t := &Templates{set: make(map[string]*template.Template)}
+	for name, source := range sources {
+		if source == "" {
+			continue
+		}
+		data, err := SampleData(name, SamplePair())
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := template.New(name).Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+		}
+		if err := parsed.Execute(&strings.Builder{}, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s template with %s: %w", name, templateData[name], err)
+		}
+		t.set[name] = parsed
+	}
+	return t, nil
+}
+
+var defaultTemplates = mustParseTemplates(defaultSources)
+
+func mustParseTemplates(sources map[string]string) *Templates {
+	t, err := ParseTemplates(sources)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// DefaultTemplates are the plain text messages sent to Telegram for names a
+// config does not override.
+func DefaultTemplates() *Templates {
+	return defaultTemplates
+}
+
+// Has reports whether there is a template by that name.
+func (t *Templates) Has(name string) bool {
+	return t != nil && t.set[name] != nil
+}
+
+// Render executes the named template with data, trimming trailing line
+// breaks. It reports false when there is no such template.
+func (t *Templates) Render(name string, data any) (string, bool, error) {
+	if !t.Has(name) {
+		return "", false, nil
+	}
+	var b strings.Builder
+	if err := t.set[name].Execute(&b, data); err != nil {
+		return "", true, fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return strings.TrimRight(b.String(), "\n"), true, nil
+}
+
+// mustRender renders a default template, which ParseTemplates has already
+// rendered once, so it cannot fail for lack of a field.
+func mustRender(name string, data any) string {
+	message, _, err := defaultTemplates.Render(name, data)
+	if err != nil {
+		notifyLog.Error("Failed to render default template", "template", name, logging.Err(err))
+	}
+	return message
+}
+
+// templated gives a channel templates that can be swapped on config reload.
+type templated struct {
+	templates atomic.Pointer[Templates]
+}
+
+func (t *templated) SetTemplates(templates *Templates) {
+	t.templates.Store(templates)
+}
+
+// render renders the channel's template by name. It reports false when there
+// is none or it fails, so the built-in message goes out instead.
+func (t *templated) render(name string, data any) (string, bool) {
+	message, ok, err := t.templates.Load().Render(name, data)
+	if err != nil {
+		notifyLog.Error("Failed to render notification template", "template", name, logging.Err(err))
+		return "", false
+	}
+	return message, ok
+}
+
+// SamplePair is a made-up listing to preview and check templates against.
+func SamplePair() types.RaydiumPair {
+	const mint = "7GCihgDB8fe6KNjn2MYtkzZcRjQy3t9GHdC8uHYmW2hr"
+	return types.RaydiumPair{
+		Name:      "Popcat",
+		Symbol:    "POPCAT",
+		Address:   mint,
+		Timestamp: time.Now().Add(-12 * time.Minute).Format(time.RFC3339),
+		Market:    "8BnEgHoWFysVcuFFX7QztDmzuH8r5ZFvyP3sYwn1XTh6",
+		Liquidity: 48312,
+		Price:     0.00125,
+		Volume24h: 183400,
+		MarketCap: 1250000,
+		Pool: types.RaydiumPool{
+			BaseMint:  mint,
+			QuoteMint: "So11111111111111111111111111111111111111112",
+		},
+	}
+}
+
+// SampleData is what the named template is rendered with in a notification
+// about pair.
+func SampleData(name string, pair types.RaydiumPair) (any, error) {
+	now := time.Now()
+	switch name {
+	case TEMPLATE_NEW_PAIR:
+		return pair, nil
+	case TEMPLATE_SAFETY_REPORT:
+		token := sampleReport(pair)
+		return &token, nil
+	case TEMPLATE_ALERT:
+		return types.Alert{Mint: pair.Address, Symbol: pair.Symbol, Kind: "liquidity_removed", Severity: types.SeverityCritical,
+			Message: "Liquidity fell 85% from its peak (48312.00 -> 7246.80)", Time: now}, nil
+	case TEMPLATE_TRADE:
+		return types.Trade{Side: types.TradeBuy, Mint: pair.Address, Symbol: pair.Symbol, Amount: 0.25,
+			Signature: "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW",
+			Reason:    "passed filters", Time: now}, nil
+	case TEMPLATE_DIGEST:
+		token := sampleReport(pair)
+		return Digest{Since: now.Add(-DEFAULT_DIGEST_INTERVAL), Until: now, Entries: []DigestEntry{
+			withTime(newPairNotification(pair), now.Add(-4*time.Minute)),
+			withTime(safetyReportNotification(token), now.Add(-3*time.Minute)),
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown template %q, expected one of %s", name, strings.Join(TemplateNames(), ", "))
+}
+
+func sampleReport(pair types.RaydiumPair) types.TokenReport {
+	report := types.NewSafetyReport(pair.Address, pair.Symbol)
+	report.Add(types.SafetyCheck{Name: "liquidity", Status: types.CheckPass, Value: fmt.Sprintf("$%.2f", pair.Liquidity),
+		Threshold: ">= $10000.00", Source: "raydium"})
+	report.Add(types.SafetyCheck{Name: "holder_count", Status: types.CheckPass, Value: "412", Threshold: ">= 100", Source: "solscan"})
+	report.Add(types.SafetyCheck{Name: "social_presence", Status: types.CheckWarn, Value: "no twitter", Source: "metadata"})
+	return types.TokenReport{
+		Pair:   pair,
+		Report: *report,
+		Safety: types.TokenSafetyMetrics{HolderCount: 412, TopHolderShare: 0.08},
+		Scores: []types.ScoreBreakdown{{Model: "heuristic", Total: 72.5}},
+	}
+}
+
+// withTime is n's digest entry as if added at the given time.
+func withTime(n notification, at time.Time) DigestEntry {
+	entry := n.entry
+	entry.Time, entry.Kind = at, n.kind
+	return entry
+}
@@ -0,0 +1,108 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+func TestDefaultTemplatesMatchTelegramMessages(t *testing.T) {
+	token := sampleToken()
+	trade := types.Trade{Side: types.TradeSell, Mint: sampleMint, Symbol: "POPCAT", Amount: 1500, Reason: "stop loss", Signature: "5sig"}
+
+	for _, test := range []struct {
+		name string
+		data any
+		want string
+	}{
+		{TEMPLATE_NEW_PAIR, token.Pair, "🚀 New token found: Popcat (" + sampleMint + ")\nLiquidity: $48312.00"},
+		{TEMPLATE_SAFETY_REPORT, &token, "🛡 Safety report for POPCAT (" + sampleMint + "): passed\n" + FormatSafetyReport(token.Report)},
+		{TEMPLATE_TRADE, trade, "🔴 Sold 1500 POPCAT (" + sampleMint + ")\nReason: stop loss\nTx: " + SOLSCAN_TX_URL + "5sig"},
+	} {
+		got, ok, err := DefaultTemplates().Render(test.name, test.data)
+		if err != nil || !ok {
+			t.Fatalf("%s: rendered %v, %v", test.name, ok, err)
+		}
+		if got != test.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseTemplatesRejectsUnknownFields(t *testing.T) {
+	_, err := ParseTemplates(map[string]string{TEMPLATE_ALERT: "{{.Severity}} {{.Liquidity}}"})
+	if err == nil || !strings.Contains(err.Error(), "Liquidity") {
+		t.Errorf("got %v, want an error naming the field", err)
+	}
+	if _, err := ParseTemplates(map[string]string{"listing": "{{.Symbol}}"}); err == nil {
+		t.Errorf("parsed a template with an unknown name")
+	}
+}
+
+func TestTemplateHelpers(t *testing.T) {
+	templates, err := ParseTemplates(map[string]string{
+		TEMPLATE_SAFETY_REPORT: `{{usd .Pair.MarketCap}} {{percent .Safety.TopHolderShare}} {{signedPercent -0.125}} {{duration 9000000000000}} {{birdeye .Pair.Address}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := sampleToken()
+	token.Safety.TopHolderShare = 0.08
+
+	got, _, err := templates.Render(TEMPLATE_SAFETY_REPORT, &token)
+	want := "$1.25M 8.0% -12.5% 2h30m " + BIRDEYE_TOKEN_URL + sampleMint + "?chain=solana"
+	if err != nil || got != want {
+		t.Errorf("got %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		45 * time.Second:              "45s",
+		90 * time.Second:              "1m30s",
+		2*time.Hour + 15*time.Minute:  "2h15m",
+		76*time.Hour + 10*time.Minute: "3d4h",
+		-3 * time.Minute:              "-3m0s",
+	} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTemplatesReplaceWebhookMessages(t *testing.T) {
+	templates, err := ParseTemplates(map[string]string{TEMPLATE_NEW_PAIR: "{{.Symbol}} listed at {{usd .Liquidity}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	discordStub := newWebhookStub(t)
+	discord := NewDiscordNotifier(discordStub.URL)
+	discord.SetTemplates(templates)
+	discord.NotifyNewPair(sampleToken().Pair)
+	discord.NotifyAlert(types.Alert{Mint: sampleMint, Symbol: "POPCAT", Severity: types.SeverityWarning})
+
+	slackStub := newWebhookStub(t)
+	slack := NewSlackNotifier(slackStub.URL)
+	slack.SetTemplates(templates)
+	slack.NotifyNewPair(sampleToken().Pair)
+
+	var discordMessages [2]discordMessage
+	for i, post := range discordStub.posts() {
+		json.Unmarshal([]byte(post), &discordMessages[i])
+	}
+	if got := discordMessages[0]; got.Content != "POPCAT listed at $48.3K" || len(got.Embeds) > 0 {
+		t.Errorf("discord new pair = %+v, want the template as plain content", got)
+	}
+	if len(discordMessages[1].Embeds) != 1 {
+		t.Errorf("discord alert = %+v, want the built-in embed without a template", discordMessages[1])
+	}
+
+	message := decodeSlack(t, slackStub.posts()[0])
+	if message.Text != "POPCAT listed at $48.3K" || len(message.Blocks) > 0 {
+		t.Errorf("slack new pair = %+v, want the template as plain text", message)
+	}
+}
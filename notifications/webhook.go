@@ -0,0 +1,120 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WEBHOOK_TIMEOUT     = 10 * time.Second
+	WEBHOOK_MAX_RETRIES = 3                // Retries of a rate-limited post
+	WEBHOOK_MAX_WAIT    = 60 * time.Second // Longest rate-limit wait honoured
+	WEBHOOK_BODY_LIMIT  = 4096             // Bytes of a response body kept for errors
+)
+
+// webhook posts JSON to an incoming-webhook URL. Posts go out one at a time so
+// a rate limit reported by one response holds back the next.
+type webhook struct {
+	mu        sync.Mutex
+	url       string
+	client    *http.Client
+	log       *slog.Logger
+	notBefore time.Time // Set when the service says the bucket is empty
+}
+
+func newWebhook(url string, log *slog.Logger) *webhook {
+	return &webhook{url: url, client: &http.Client{Timeout: WEBHOOK_TIMEOUT}, log: log}
+}
+
+func (w *webhook) setURL(url string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.url = url
+}
+
+// post sends payload, waiting out and retrying 429 responses. With no URL it does nothing.
+func (w *webhook) post(payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.url == "" {
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		time.Sleep(time.Until(w.notBefore))
+
+		resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(data))
+		if err != nil {
+			// The webhook URL is the secret, so it is left out of the error
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return fmt.Errorf("failed to post webhook: %w", err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, WEBHOOK_BODY_LIMIT))
+		resp.Body.Close()
+
+		w.noteRateLimit(resp.Header)
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < WEBHOOK_MAX_RETRIES {
+			wait := retryAfter(resp.Header, body)
+			w.log.Warn("Webhook rate limited, retrying", "wait", wait, "attempt", attempt+1)
+			w.notBefore = time.Now().Add(wait)
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return nil
+	}
+}
+
+// noteRateLimit holds back the next post when Discord-style headers say the
+// bucket is empty.
+func (w *webhook) noteRateLimit(header http.Header) {
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	if wait, ok := parseSeconds(header.Get("X-RateLimit-Reset-After")); ok {
+		w.notBefore = time.Now().Add(wait)
+	}
+}
+
+// retryAfter reads how long a 429 asks to wait: the Retry-After header, or
+// Discord's retry_after body field, or a second if neither is given.
+func retryAfter(header http.Header, body []byte) time.Duration {
+	if wait, ok := parseSeconds(header.Get("Retry-After")); ok {
+		return wait
+	}
+	var limited struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if json.Unmarshal(body, &limited) == nil && limited.RetryAfter > 0 {
+		return min(time.Duration(limited.RetryAfter*float64(time.Second)), WEBHOOK_MAX_WAIT)
+	}
+	return time.Second
+}
+
+// parseSeconds parses a whole or fractional number of seconds, capped at WEBHOOK_MAX_WAIT.
+func parseSeconds(value string) (time.Duration, bool) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return min(time.Duration(seconds*float64(time.Second)), WEBHOOK_MAX_WAIT), true
+}
@@ -0,0 +1,184 @@
+package notifications
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+// stubResponse is one canned reply of a webhookStub.
+type stubResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+// webhookStub records the bodies posted to it and replies with the canned
+// responses in turn, then with 204s.
+type webhookStub struct {
+	*httptest.Server
+	mu        sync.Mutex
+	responses []stubResponse
+	bodies    []string
+	times     []time.Time
+}
+
+func newWebhookStub(t *testing.T, responses ...stubResponse) *webhookStub {
+	t.Helper()
+	stub := &webhookStub{responses: responses}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with content type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+
+		stub.mu.Lock()
+		stub.bodies = append(stub.bodies, string(body))
+		stub.times = append(stub.times, time.Now())
+		response := stubResponse{status: http.StatusNoContent}
+		if len(stub.responses) > 0 {
+			response, stub.responses = stub.responses[0], stub.responses[1:]
+		}
+		stub.mu.Unlock()
+
+		for name, value := range response.header {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(response.status)
+		io.WriteString(w, response.body)
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *webhookStub) posts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+// gap is the time between the first two posts.
+func (s *webhookStub) gap() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.times) < 2 {
+		return 0
+	}
+	return s.times[1].Sub(s.times[0])
+}
+
+func TestWebhookRetriesAfterRetryAfterHeader(t *testing.T) {
+	stub := newWebhookStub(t, stubResponse{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "0.05"}})
+	hook := newWebhook(stub.URL, slackLog)
+
+	if err := hook.post(map[string]string{"text": "hello"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if posts := stub.posts(); len(posts) != 2 || posts[0] != posts[1] {
+		t.Fatalf("got posts %q, want the same body twice", posts)
+	}
+	if gap := stub.gap(); gap < 50*time.Millisecond {
+		t.Errorf("retried after %v, want at least 50ms", gap)
+	}
+}
+
+func TestWebhookRetriesAfterDiscordBody(t *testing.T) {
+	stub := newWebhookStub(t, stubResponse{status: http.StatusTooManyRequests, body: `{"message": "You are being rate limited.", "retry_after": 0.05, "global": false}`})
+	hook := newWebhook(stub.URL, discordLog)
+
+	if err := hook.post(map[string]string{"content": "hello"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if gap := stub.gap(); gap < 50*time.Millisecond {
+		t.Errorf("retried after %v, want at least 50ms", gap)
+	}
+}
+
+func TestWebhookGivesUpWhenStillLimited(t *testing.T) {
+	limited := stubResponse{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "0"}, body: "slow down"}
+	responses := make([]stubResponse, WEBHOOK_MAX_RETRIES+1)
+	for i := range responses {
+		responses[i] = limited
+	}
+	stub := newWebhookStub(t, responses...)
+	hook := newWebhook(stub.URL, slackLog)
+
+	err := hook.post(map[string]string{"text": "hello"})
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("got %v, want a 429 error", err)
+	}
+	if posts := stub.posts(); len(posts) != WEBHOOK_MAX_RETRIES+1 {
+		t.Errorf("got %d posts, want %d", len(posts), WEBHOOK_MAX_RETRIES+1)
+	}
+}
+
+func TestWebhookWaitsForEmptyBucket(t *testing.T) {
+	stub := newWebhookStub(t, stubResponse{status: http.StatusNoContent,
+		header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset-After": "0.05"}})
+	hook := newWebhook(stub.URL, discordLog)
+
+	for i := 0; i < 2; i++ {
+		if err := hook.post(map[string]string{"content": "hello"}); err != nil {
+			t.Fatalf("post %d: %v", i, err)
+		}
+	}
+	if gap := stub.gap(); gap < 50*time.Millisecond {
+		t.Errorf("second post after %v, want at least 50ms", gap)
+	}
+}
+
+func TestWebhookReportsErrorStatus(t *testing.T) {
+	stub := newWebhookStub(t, stubResponse{status: http.StatusNotFound, body: "no_team"})
+	hook := newWebhook(stub.URL, slackLog)
+
+	err := hook.post(map[string]string{"text": "hello"})
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "no_team") {
+		t.Fatalf("got %v, want a 404 error with the body", err)
+	}
+	if posts := stub.posts(); len(posts) != 1 {
+		t.Errorf("got %d posts, want no retries", len(posts))
+	}
+}
+
+func TestWebhookWithoutURLDoesNothing(t *testing.T) {
+	if err := newWebhook("", slackLog).post(map[string]string{"text": "hello"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+}
+
+const sampleMint = "7GCihgDB8fe6KNjn2MYtkzZcRjQy3t9GHdC8uHYmW2hr"
+
+// sampleToken is a token that passed the filters, as the market monitor reports it.
+func sampleToken() types.TokenReport {
+	report := types.NewSafetyReport(sampleMint, "POPCAT")
+	report.Add(types.SafetyCheck{Name: "liquidity", Status: types.CheckPass, Value: "$48312.00", Threshold: ">= $10000.00", Source: "raydium"})
+	report.Add(types.SafetyCheck{Name: "holder_count", Status: types.CheckPass, Value: "412", Threshold: ">= 100", Source: "solscan"})
+	report.Add(types.SafetyCheck{Name: "social_presence", Status: types.CheckWarn, Value: "no twitter", Source: "metadata"})
+	return types.TokenReport{
+		Pair:   types.RaydiumPair{Name: "Popcat", Symbol: "POPCAT", Address: sampleMint, Liquidity: 48312, MarketCap: 1250000},
+		Report: *report,
+		Safety: types.TokenSafetyMetrics{HolderCount: 412},
+		Scores: []types.ScoreBreakdown{{Model: "heuristic", Total: 72.5}},
+	}
+}
+
+func TestWebhookErrorLeavesOutURL(t *testing.T) {
+	stub := newWebhookStub(t)
+	secret := stub.URL + "/api/webhooks/123/secret-token"
+	stub.Close()
+	hook := newWebhook(secret, discordLog)
+
+	err := hook.post(map[string]string{"content": "hello"})
+	if err == nil {
+		t.Fatal("post to a closed server succeeded")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error repeats the webhook URL: %v", err)
+	}
+}
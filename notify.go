@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"grind/config"
+	"grind/logging"
+	"grind/notifications"
+	"time"
+)
+
+// notifiers are the notification channels built from the config: the
+// multiplexer everything notifies through and the backends behind it.
+type notifiers struct {
+	*notifications.Multiplexer
+	telegram *notifications.TelegramNotifier
+	commands bool                           // Whether the telegram bot takes commands, and so buy buttons work
+	discord  *notifications.DiscordNotifier // Nil until a webhook URL is set
+	slack    *notifications.SlackNotifier
+}
+
+func newNotifiers(cfg *config.Config) (*notifiers, error) {
+	update, err := prepareNotifiers(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	telegram := cfg.Notifications.Telegram
+	n := &notifiers{
+		Multiplexer: notifications.NewMultiplexer(),
+		telegram:    notifications.NewTelegramNotifier(telegram.BotToken, telegram.ChatID),
+	}
+	if err := n.Add("telegram", n.telegram, update.options["telegram"]); err != nil {
+		n.Close()
+		return nil, fmt.Errorf("failed to add telegram notifications: %w", err)
+	}
+	n.apply(update)
+	return n, nil
+}
+
+// notifierUpdate is a config's policy, channel options and templates, checked
+// and parsed so that applying it cannot fail.
+type notifierUpdate struct {
+	cfg            *config.Config
+	dedupWindow    time.Duration
+	digestInterval time.Duration
+	options        map[string]notifications.ChannelOptions
+	templates      map[string]*notifications.Templates
+}
+
+func prepareNotifiers(cfg *config.Config) (notifierUpdate, error) {
+	update := notifierUpdate{
+		cfg:            cfg,
+		dedupWindow:    time.Duration(cfg.Notifications.DedupWindowSeconds) * time.Second,
+		digestInterval: time.Duration(cfg.Notifications.DigestSeconds) * time.Second,
+		options:        make(map[string]notifications.ChannelOptions),
+		templates:      make(map[string]*notifications.Templates),
+	}
+	if err := notifications.ValidatePolicy(update.dedupWindow, update.digestInterval); err != nil {
+		return notifierUpdate{}, err
+	}
+
+	for _, channel := range []struct {
+		name   string
+		config config.ChannelConfig
+	}{
+		{"telegram", cfg.Notifications.Telegram.ChannelConfig},
+		{"discord", cfg.Notifications.Discord.ChannelConfig},
+		{"slack", cfg.Notifications.Slack.ChannelConfig},
+	} {
+		options := channelOptions(channel.config)
+		if err := options.Validate(channel.name); err != nil {
+			return notifierUpdate{}, err
+		}
+		templates, err := channelTemplates(cfg, channel.name, channel.config)
+		if err != nil {
+			return notifierUpdate{}, err
+		}
+		update.options[channel.name] = options
+		update.templates[channel.name] = templates
+	}
+	return update, nil
+}
+
+// apply pushes changed credentials, policy, channel options and templates into
+// the channels, adding a webhook channel the first time its URL is set.
+func (n *notifiers) apply(update notifierUpdate) {
+	// The policy and options were checked by prepareNotifiers
+	n.SetPolicy(update.dedupWindow, update.digestInterval)
+
+	telegram := update.cfg.Notifications.Telegram
+	n.telegram.SetCredentials(telegram.BotToken, telegram.ChatID)
+	if n.commands {
+		n.telegram.SetBuyPresets(telegram.Bot.BuyPresets)
+		n.telegram.SetMaxBuy(telegram.Bot.MaxBuy)
+	}
+	n.SetOptions("telegram", update.options["telegram"])
+	n.telegram.SetTemplates(update.templates["telegram"])
+
+	discord := update.cfg.Notifications.Discord
+	if n.discord != nil {
+		n.discord.SetTemplates(update.templates["discord"])
+		n.discord.SetWebhookURL(discord.WebhookURL)
+		n.SetOptions("discord", update.options["discord"])
+	} else if discord.WebhookURL != "" {
+		n.discord = notifications.NewDiscordNotifier(discord.WebhookURL)
+		n.discord.SetTemplates(update.templates["discord"])
+		if err := n.Add("discord", n.discord, update.options["discord"]); err != nil {
+			mainLog.Error("Failed to add discord notifications", logging.Err(err))
+		}
+	}
+
+	slack := update.cfg.Notifications.Slack
+	if n.slack != nil {
+		n.slack.SetTemplates(update.templates["slack"])
+		n.slack.SetWebhookURL(slack.WebhookURL)
+		n.SetOptions("slack", update.options["slack"])
+	} else if slack.WebhookURL != "" {
+		n.slack = notifications.NewSlackNotifier(slack.WebhookURL)
+		n.slack.SetTemplates(update.templates["slack"])
+		if err := n.Add("slack", n.slack, update.options["slack"]); err != nil {
+			mainLog.Error("Failed to add slack notifications", logging.Err(err))
+		}
+	}
+}
+
+func channelOptions(c config.ChannelConfig) notifications.ChannelOptions {
+	return notifications.ChannelOptions{MinSeverity: c.MinSeverity, MaxPerMinute: c.MaxPerMinute, Digest: c.Digest}
+}
+
+// channelTemplates parses the named channel's templates over the shared ones.
+func channelTemplates(cfg *config.Config, name string, channel config.ChannelConfig) (*notifications.Templates, error) {
+	templates, err := notifications.ParseTemplates(cfg.Notifications.ChannelTemplates(channel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s templates: %w", name, err)
+	}
+	return templates, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"grind/services"
+	"io"
+	"os"
+	"sort"
+)
+
+// positionView is an open position, valued at the current price with -live.
+type positionView struct {
+	services.Position
+	Price  float64 `json:"price,omitempty"`  // USD per token now
+	Value  float64 `json:"value,omitempty"`  // USD
+	Return float64 `json:"return,omitempty"` // Fraction gained since entry
+}
+
+func runPositions(args []string) error {
+	flags := flag.NewFlagSet("positions", flag.ExitOnError)
+	var source configSource
+	source.register(flags)
+	outputFormat := formatFlag(flags, FORMAT_TABLE, FORMAT_JSON)
+	live := flags.Bool("live", false, "value positions at current prices from the pairs feed")
+	flags.Parse(args)
+
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+	cfg, _, err := source.load()
+	if err != nil {
+		return err
+	}
+
+	positions := services.NewPositionBook(cfg.Storage.PositionsPath)
+	if err := positions.Load(); err != nil {
+		return fmt.Errorf("failed to load positions: %w", err)
+	}
+
+	views := make([]positionView, 0)
+	for _, position := range positions.All() {
+		views = append(views, positionView{Position: position})
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].OpenedAt.Before(views[j].OpenedAt) })
+
+	if *live && len(views) > 0 {
+		if err := configureServices(cfg); err != nil {
+			return err
+		}
+		pairs, err := services.FetchRaydiumPairs()
+		if err != nil {
+			return fmt.Errorf("failed to fetch prices: %w", err)
+		}
+		prices := make(map[string]float64, len(pairs))
+		for _, pair := range pairs {
+			prices[pair.Address] = pair.Price
+		}
+		for i := range views {
+			views[i].Price = prices[views[i].Mint]
+			views[i].Value = views[i].Price * views[i].Amount
+			if views[i].EntryPrice > 0 && views[i].Price > 0 {
+				views[i].Return = views[i].Price/views[i].EntryPrice - 1
+			}
+		}
+	}
+
+	return printResult(os.Stdout, format, views, func(w io.Writer) {
+		if len(views) == 0 {
+			fmt.Fprintln(w, "No open positions")
+			return
+		}
+		if *live {
+			fmt.Fprintln(w, "SYMBOL\tMINT\tAMOUNT\tENTRY\tPRICE\tVALUE\tRETURN\tOPENED")
+		} else {
+			fmt.Fprintln(w, "SYMBOL\tMINT\tAMOUNT\tENTRY\tOPENED")
+		}
+		for _, view := range views {
+			opened := view.OpenedAt.Local().Format("2006-01-02 15:04")
+			if *live {
+				fmt.Fprintf(w, "%s\t%s\t%g\t$%.8f\t$%.8f\t$%.2f\t%+.1f%%\t%s\n", view.Symbol, view.Mint, view.Amount,
+					view.EntryPrice, view.Price, view.Value, view.Return*100, opened)
+			} else {
+				fmt.Fprintf(w, "%s\t%s\t%g\t$%.8f\t%s\n", view.Symbol, view.Mint, view.Amount, view.EntryPrice, opened)
+			}
+		}
+	})
+}
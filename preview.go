@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"grind/config"
+	"grind/notifications"
+	"os"
+	"strings"
+)
+
+// runPreview renders notification templates the way a channel would send
+// them, against the sample pair or one read from a JSON file.
+func runPreview(args []string) error {
+	flags := flag.NewFlagSet("preview", flag.ExitOnError)
+	var source configSource
+	source.register(flags)
+	channel := flags.String("channel", "telegram", "channel whose templates to render: telegram, discord or slack")
+	templateFile := flags.String("template", "", "render this template file instead of the configured one, for a single template")
+	pairFile := flags.String("pair", "", "JSON file with a RaydiumPair to render against instead of the sample")
+	names, err := argsAfterFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		names = notifications.TemplateNames()
+	}
+	if *channel != "telegram" && *channel != "discord" && *channel != "slack" {
+		return fmt.Errorf("unknown channel %q, expected telegram, discord or slack", *channel)
+	}
+	if *templateFile != "" && len(names) != 1 {
+		return errors.New("usage: grind preview -template <file> <" + strings.Join(notifications.TemplateNames(), "|") + ">")
+	}
+
+	pair := notifications.SamplePair()
+	if *pairFile != "" {
+		data, err := os.ReadFile(*pairFile)
+		if err != nil {
+			return fmt.Errorf("failed to read pair: %w", err)
+		}
+		if err := json.Unmarshal(data, &pair); err != nil {
+			return fmt.Errorf("failed to parse pair: %w", err)
+		}
+	}
+
+	sources, err := previewSources(source, *channel, *templateFile, names)
+	if err != nil {
+		return err
+	}
+	templates, err := notifications.ParseTemplates(sources)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		data, err := notifications.SampleData(name, pair)
+		if err != nil {
+			return err
+		}
+
+		message, ok, err := templates.Render(name, data)
+		origin := "configured"
+		if !ok {
+			if *channel != "telegram" {
+				fmt.Printf("# %s (%s): no template, the built-in message is sent\n", name, *channel)
+				continue
+			}
+			origin = "default"
+			message, _, err = notifications.DefaultTemplates().Render(name, data)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# %s (%s, %s)\n%s\n", name, *channel, origin, message)
+	}
+	return nil
+}
+
+// previewSources are the template sources to preview: the file given, or the
+// channel's from the config.
+func previewSources(source configSource, channel, templateFile string, names []string) (map[string]string, error) {
+	if templateFile != "" {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		return map[string]string{names[0]: string(data)}, nil
+	}
+
+	cfg, _, err := source.load()
+	if err != nil {
+		return nil, err
+	}
+	channels := map[string]config.ChannelConfig{
+		"telegram": cfg.Notifications.Telegram.ChannelConfig,
+		"discord":  cfg.Notifications.Discord.ChannelConfig,
+		"slack":    cfg.Notifications.Slack.ChannelConfig,
+	}
+	return cfg.Notifications.ChannelTemplates(channels[channel]), nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"grind/analytics"
+	"grind/config"
+	"grind/logging"
+	"grind/services"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const CONFIG_POLL_INTERVAL = 2 * time.Second
+
+var configLog = logging.For("config")
+
+// restartOnly are the settings only read at startup. A reload reports changes
+// to them but they take effect after a restart.
+var restartOnly = []string{
+	"storage.databasePath",
+	"storage.trackerPath",
+	"storage.positionsPath",
+	"storage.persistCache",
+	"sources.priceSource",
+	"sources.priceSampleSeconds",
+	"safety.rugWatch.checkSeconds",
+	"metrics.",
+	"api.",
+	"notifications.telegram.bot.enabled",
+	"notifications.telegram.bot.allowedChatIds",
+}
+
+// runtimeServices are the long-lived objects a config change is pushed into.
+type runtimeServices struct {
+	notifier *notifiers
+	tracker  *services.TokenTracker
+	watcher  *services.RugWatcher
+}
+
+// configureServices pushes cfg into the services package, as every command
+// needs. Nothing is changed unless all of cfg is accepted.
+func configureServices(cfg *config.Config) error {
+	prepared, err := prepareServices(cfg)
+	if err != nil {
+		return err
+	}
+	prepared.apply()
+	return nil
+}
+
+// preparedServices is what configureServices pushes into the services
+// package, built and checked so that applying it cannot fail.
+type preparedServices struct {
+	cfg      *config.Config
+	analyzer *analytics.TokenAnalyzer
+	scorers  []analytics.Scorer
+}
+
+func prepareServices(cfg *config.Config) (preparedServices, error) {
+	analyzer, err := analytics.NewTokenAnalyzer(cfg.AnalyzerConfig())
+	if err != nil {
+		return preparedServices{}, fmt.Errorf("failed to build analyzer: %w", err)
+	}
+	scorers, err := analytics.NewScorers(cfg.Scoring, cfg.AnalyzerConfig())
+	if err != nil {
+		return preparedServices{}, fmt.Errorf("failed to build scorers: %w", err)
+	}
+	if err := services.CheckFailurePolicies(cfg.Safety.FailurePolicies); err != nil {
+		return preparedServices{}, err
+	}
+	if err := cfg.Logging.Validate(); err != nil {
+		return preparedServices{}, fmt.Errorf("failed to set up logging: %w", err)
+	}
+	return preparedServices{cfg: cfg, analyzer: analyzer, scorers: scorers}, nil
+}
+
+func (p preparedServices) apply() {
+	// The failure policies and logging config were checked by prepareServices
+	services.SetFailurePolicies(p.cfg.Safety.FailurePolicies)
+	logging.Setup(p.cfg.Logging)
+
+	services.SetSettings(serviceSettings(p.cfg))
+	services.ConfigureCache(p.cfg.Storage.CacheSize, cacheTTLs(p.cfg))
+	services.SetAnalysis(p.analyzer, p.scorers)
+	services.SetSocialProbe(services.NewHTTPSocialProbe(p.cfg.Sources.TwitterBearerToken, p.cfg.Notifications.Telegram.BotToken))
+}
+
+// serviceSettings are the endpoints, timings and wallet the services package runs with.
+func serviceSettings(cfg *config.Config) services.Settings {
+	return services.Settings{
+		RaydiumPairsURL: cfg.Sources.RaydiumPairsURL,
+		RaydiumPoolURL:  cfg.Sources.RaydiumPoolURL,
+		SolscanURL:      cfg.Sources.SolscanURL,
+		GoPlusURL:       cfg.Sources.GoPlusURL,
+		RPCURL:          cfg.RPC.URL,
+		RPCWebsocketURL: cfg.RPC.WebsocketURL,
+		WalletAddress:   cfg.Trading.WalletAddress,
+		PrivateKey:      cfg.Trading.PrivateKey,
+		MaxSlippage:     cfg.Trading.MaxSlippage,
+		GoPlusBatchSize: cfg.Sources.GoPlusBatchSize,
+		MaxTax:          cfg.Safety.MaxTax,
+		FetchInterval:   time.Duration(cfg.Sources.FetchIntervalSeconds) * time.Second,
+		RequestTimeout:  time.Duration(cfg.Sources.RequestTimeoutSeconds) * time.Second,
+	}
+}
+
+// cacheTTLs are the token cache's TTLs by check.
+func cacheTTLs(cfg *config.Config) map[services.CacheCheck]time.Duration {
+	ttls := make(map[services.CacheCheck]time.Duration, len(cfg.Storage.CacheTTLSeconds))
+	for check, seconds := range cfg.Storage.CacheTTLSeconds {
+		ttls[services.CacheCheck(check)] = time.Duration(seconds) * time.Second
+	}
+	return ttls
+}
+
+// applyConfig pushes cfg into everything that can change while scanning. Every
+// part is built and checked first, so a config that fails leaves all of the
+// running one in place.
+func applyConfig(cfg *config.Config, runtime runtimeServices) error {
+	prepared, err := prepareServices(cfg)
+	if err != nil {
+		return err
+	}
+	update, err := prepareNotifiers(cfg)
+	if err != nil {
+		return err
+	}
+
+	prepared.apply()
+	runtime.notifier.apply(update)
+	runtime.tracker.SetLimits(cfg.Trading.MaxTokensToTrack, cfg.MaxMarketAge())
+	runtime.watcher.SetConfig(cfg.Safety.RugWatch)
+	return nil
+}
+
+// reloader re-reads the config files when any of them changes or on SIGHUP.
+type reloader struct {
+	paths   []string
+	current *config.Config
+	runtime runtimeServices
+}
+
+func (r *reloader) run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	changed := make(chan struct{}, 1)
+	for _, path := range r.paths {
+		go config.WatchFile(ctx, path, CONFIG_POLL_INTERVAL, changed)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			configLog.Info("Received SIGHUP, reloading config", "files", strings.Join(r.paths, " + "))
+			r.reload()
+		case <-changed:
+			configLog.Info("Config changed, reloading", "files", strings.Join(r.paths, " + "))
+			r.reload()
+		}
+	}
+}
+
+// reload swaps in the new config if it is valid and keeps the old one otherwise.
+func (r *reloader) reload() {
+	next, err := config.LoadConfig(r.paths...)
+	if err != nil {
+		configLog.Error("Rejected config reload", logging.Err(err))
+		return
+	}
+	if err := next.Validate(); err != nil {
+		configLog.Error("Rejected config reload", logging.Err(err))
+		return
+	}
+
+	changes, err := config.Diff(r.current, next)
+	if err != nil {
+		configLog.Error("Rejected config reload", logging.Err(err))
+		return
+	}
+	if len(changes) == 0 {
+		configLog.Info("Config reloaded, nothing changed")
+		return
+	}
+
+	if err := applyConfig(next, r.runtime); err != nil {
+		configLog.Error("Rejected config reload", logging.Err(err))
+		return
+	}
+	r.current = next
+
+	configLog.Info("Config reloaded", "changes", len(changes))
+	for _, change := range changes {
+		configLog.Info("Config changed", "path", change.Path, "old", change.Old, "new", change.New,
+			"restartRequired", needsRestart(change.Path))
+	}
+}
+
+func needsRestart(path string) bool {
+	for _, prefix := range restartOnly {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
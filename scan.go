@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"grind/api"
+	"grind/db"
+	"grind/events"
+	"grind/logging"
+	"grind/metrics"
+	"grind/notifications"
+	"grind/services"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// runScan runs the scanner until interrupted.
+func runScan(args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	var source configSource
+	source.register(flags)
+	flags.Parse(args)
+
+	// Setup signal handling for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, configFiles, err := source.load()
+	if err != nil {
+		return err
+	}
+	mainLog.Info("Loaded config", "files", strings.Join(configFiles, " + "))
+
+	notifier, err := newNotifiers(cfg)
+	if err != nil {
+		return err
+	}
+	defer notifier.Close()
+	bot := cfg.Notifications.Telegram.Bot
+	notifier.commands = bot.Enabled
+	tracker := services.NewTokenTracker(cfg.Storage.TrackerPath)
+	positions := services.NewPositionBook(cfg.Storage.PositionsPath)
+
+	watcher := services.NewRugWatcher(cfg.Safety.RugWatch, tracker, notifier,
+		services.NewEmergencySeller(positions, notifier))
+
+	runtime := runtimeServices{notifier: notifier, tracker: tracker, watcher: watcher}
+	if err := applyConfig(cfg, runtime); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	database, err := db.NewDatabase(cfg.Storage.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	if cfg.Storage.PersistCache {
+		if err := services.EnableCachePersistence(database); err != nil {
+			mainLog.Warn("Failed to load token cache", logging.Err(err))
+		}
+	}
+
+	if err := tracker.Load(); err != nil {
+		mainLog.Warn("Failed to restore tracked tokens", logging.Err(err))
+	}
+	if err := positions.Load(); err != nil {
+		return fmt.Errorf("failed to load positions: %w", err)
+	}
+
+	priceSource, err := services.NewPriceSource(cfg.Sources.PriceSource)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	collector := services.NewPriceCollector(tracker, priceSource, database, cfg.PriceSampleInterval())
+	collector.AddListener(watcher)
+
+	// Start services
+	go services.TrackNewTokens(database, notifier, tracker)
+	go collector.Run(ctx)
+	go watcher.Run(ctx)
+	if cfg.Metrics.Enabled {
+		go func() {
+			mainLog.Info("Serving metrics", "addr", cfg.Metrics.Listen)
+			if err := metrics.Serve(ctx, cfg.Metrics.Listen); err != nil {
+				mainLog.Error("Metrics server stopped", logging.Err(err))
+			}
+		}()
+	}
+	if cfg.API.Enabled {
+		server := api.NewServer(database, tracker, positions, events.Default())
+		go func() {
+			mainLog.Info("Serving API", "addr", cfg.API.Listen)
+			if err := server.Serve(ctx, cfg.API.Listen); err != nil {
+				mainLog.Error("API server stopped", logging.Err(err))
+			}
+		}()
+	}
+	if bot.Enabled {
+		control := &scanControl{tracker: tracker, positions: positions, notifier: notifier, started: time.Now()}
+		go notifications.NewTelegramBot(notifier.telegram, control, bot.AllowedChatIDs).Run(ctx)
+	}
+	go (&reloader{paths: configFiles, current: cfg, runtime: runtime}).run(ctx)
+
+	// Wait for shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	mainLog.Info("Shutting down gracefully")
+	if err := tracker.Save(); err != nil {
+		mainLog.Error("Failed to save tracked tokens", logging.Err(err))
+	}
+	return nil
+}
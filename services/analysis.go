@@ -0,0 +1,183 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"grind/analytics"
+	"grind/events"
+	"grind/types"
+)
+
+const (
+	SOURCE_RAYDIUM  = "raydium"
+	SOURCE_SOLSCAN  = "solscan"
+	SOURCE_GOPLUS   = "goplus"
+	SOURCE_SOCIAL   = "social"
+	SOURCE_METADATA = "metaplex"
+)
+
+// Analysis is the analyzer and scoring models a token is judged with. They are
+// swapped together so no token is filtered by one config and scored by another.
+type Analysis struct {
+	Analyzer *analytics.TokenAnalyzer
+	Scorers  []analytics.Scorer // Primary first
+}
+
+var (
+	tokenAnalyzerMu sync.RWMutex
+	analysis        = Analysis{
+		Analyzer: defaultTokenAnalyzer(),
+		Scorers:  []analytics.Scorer{analytics.HeuristicScorer{}},
+	}
+)
+
+func defaultTokenAnalyzer() *analytics.TokenAnalyzer {
+	analyzer, err := analytics.NewTokenAnalyzer(analytics.TokenAnalyzerConfig{})
+	if err != nil {
+		log.Fatalf("Invalid default analyzer rules: %v", err)
+	}
+	return analyzer
+}
+
+// SetAnalysis replaces the analyzer and the scoring models in one step.
+func SetAnalysis(analyzer *analytics.TokenAnalyzer, scorers []analytics.Scorer) {
+	tokenAnalyzerMu.Lock()
+	defer tokenAnalyzerMu.Unlock()
+	analysis = Analysis{Analyzer: analyzer, Scorers: scorers}
+}
+
+// SetTokenAnalyzer replaces the analyzer that decides which tokens pass.
+func SetTokenAnalyzer(analyzer *analytics.TokenAnalyzer) {
+	tokenAnalyzerMu.Lock()
+	defer tokenAnalyzerMu.Unlock()
+	analysis.Analyzer = analyzer
+}
+
+// SetScorers replaces the scoring models; the first is the primary one.
+func SetScorers(scorers []analytics.Scorer) {
+	tokenAnalyzerMu.Lock()
+	defer tokenAnalyzerMu.Unlock()
+	analysis.Scorers = scorers
+}
+
+// CurrentAnalysis is the analyzer and scorers in effect now. Hold on to it to
+// judge a batch of tokens consistently across a reload.
+func CurrentAnalysis() Analysis {
+	tokenAnalyzerMu.RLock()
+	defer tokenAnalyzerMu.RUnlock()
+	return analysis
+}
+
+// Evaluate checks the analyzer's rules against everything known about the
+// pair. The report's Passed is the filtering verdict.
+func (a Analysis) Evaluate(pair RaydiumPair, metrics TokenMetrics, safety TokenSafetyMetrics) *SafetyReport {
+	return a.Analyzer.Evaluate(pair.Address, pair.Symbol, TokenFacts(pair, metrics, safety))
+}
+
+// Score scores the token with every model, primary first.
+func (a Analysis) Score(pair RaydiumPair, metrics TokenMetrics, safety TokenSafetyMetrics) []types.ScoreBreakdown {
+	facts := TokenFacts(pair, metrics, safety)
+
+	scores := make([]types.ScoreBreakdown, 0, len(a.Scorers))
+	for _, scorer := range a.Scorers {
+		scores = append(scores, scorer.Score(facts))
+	}
+	return scores
+}
+
+// AnalyzeTokenPotential evaluates the current analyzer's rules against the pair.
+func AnalyzeTokenPotential(pair RaydiumPair, metrics TokenMetrics, safety TokenSafetyMetrics) *SafetyReport {
+	return CurrentAnalysis().Evaluate(pair, metrics, safety)
+}
+
+// TokenFacts is what the analyzer's rules and the scorers judge a token on.
+func TokenFacts(pair RaydiumPair, metrics TokenMetrics, safety TokenSafetyMetrics) *analytics.Facts {
+	return TokenFactsAt(pair, metrics, safety, time.Now())
+}
+
+// TokenFactsAt builds the facts as they stood at a past moment, for replays.
+func TokenFactsAt(pair RaydiumPair, metrics TokenMetrics, safety TokenSafetyMetrics, at time.Time) *analytics.Facts {
+	facts := analytics.NewFacts()
+	addMetricFacts(facts, pair, metrics, at)
+	addSafetyFacts(facts, safety)
+	return facts
+}
+
+// ScoreToken scores the token with every configured model, primary first.
+func ScoreToken(pair RaydiumPair, metrics TokenMetrics, safety TokenSafetyMetrics) []types.ScoreBreakdown {
+	return CurrentAnalysis().Score(pair, metrics, safety)
+}
+
+// CalculateTokenScore returns the primary model's score.
+func CalculateTokenScore(metrics TokenMetrics, safety TokenSafetyMetrics) float64 {
+	return ScoreToken(RaydiumPair{}, metrics, safety)[0].Total
+}
+
+// RunSafetyChecks evaluates only the safety rules for a bare mint address.
+func RunSafetyChecks(tokenAddress string) *SafetyReport {
+	safetyLog.Debug("Running safety checks", "mint", tokenAddress)
+
+	facts := analytics.NewFacts()
+	addSafetyFacts(facts, CheckTokenSafety(tokenAddress))
+
+	symbol := ""
+	if metadata, err := ResolveTokenMetadata(tokenAddress); err == nil {
+		symbol = metadata.Symbol
+	}
+	report := CurrentAnalysis().Analyzer.Evaluate(tokenAddress, symbol, facts)
+	events.Publish(events.SAFETY_REPORT, tokenAddress, symbol, report)
+	return report
+}
+
+func addMetricFacts(facts *analytics.Facts, pair RaydiumPair, metrics TokenMetrics, at time.Time) {
+	facts.Set(analytics.FieldLiquidity, metrics.Liquidity, SOURCE_RAYDIUM)
+	facts.Set(analytics.FieldVolume24h, metrics.Volume24h, SOURCE_SOLSCAN)
+	facts.Set(analytics.FieldMarketCap, metrics.MarketCap, SOURCE_SOLSCAN)
+
+	if createdAt, err := time.Parse(time.RFC3339, pair.Timestamp); err == nil {
+		facts.Set(analytics.FieldAgeSeconds, at.Sub(createdAt).Seconds(), SOURCE_RAYDIUM)
+	}
+}
+
+func addSafetyFacts(facts *analytics.Facts, safety TokenSafetyMetrics) {
+	if reason, unavailable := safety.Unavailable[types.CHECK_LIQUIDITY_LOCK]; unavailable {
+		facts.SetUnknown(analytics.FieldLiquidityLocked, reason, SOURCE_GOPLUS, failurePolicy(types.CHECK_LIQUIDITY_LOCK))
+		facts.SetUnknown(analytics.FieldLockDurationDays, reason, SOURCE_GOPLUS, failurePolicy(types.CHECK_LIQUIDITY_LOCK))
+	} else {
+		facts.SetBool(analytics.FieldLiquidityLocked, safety.LiquidityLocked, SOURCE_GOPLUS)
+		if safety.LiquidityLocked {
+			facts.Set(analytics.FieldLockDurationDays, safety.LiquidityLockTime.Hours()/24, SOURCE_GOPLUS)
+		}
+	}
+
+	if reason, unavailable := safety.Unavailable[types.CHECK_HONEYPOT]; unavailable {
+		facts.SetUnknown(analytics.FieldIsHoneypot, reason, SOURCE_GOPLUS, failurePolicy(types.CHECK_HONEYPOT))
+	} else {
+		facts.SetBool(analytics.FieldIsHoneypot, safety.IsHoneypot, SOURCE_GOPLUS)
+	}
+
+	if reason, unavailable := safety.Unavailable[types.CHECK_HOLDER_COUNT]; unavailable {
+		facts.SetUnknown(analytics.FieldTopHolderShare, reason, SOURCE_SOLSCAN, failurePolicy(types.CHECK_TOP_HOLDER_SHARE))
+		facts.SetUnknown(analytics.FieldHolderCount, reason, SOURCE_SOLSCAN, failurePolicy(types.CHECK_HOLDER_COUNT))
+	} else {
+		facts.Set(analytics.FieldTopHolderShare, safety.TopHolderShare, SOURCE_SOLSCAN)
+		facts.Set(analytics.FieldHolderCount, float64(safety.HolderCount), SOURCE_SOLSCAN)
+	}
+
+	if reason, unavailable := safety.Unavailable[types.CHECK_SOCIAL_PRESENCE]; unavailable {
+		facts.SetUnknown(analytics.FieldSocialScore, reason, SOURCE_SOCIAL, failurePolicy(types.CHECK_SOCIAL_PRESENCE))
+	} else {
+		facts.Set(analytics.FieldSocialScore, float64(socialScore(safety.SocialMetrics)), SOURCE_SOCIAL)
+		facts.SetBool(analytics.FieldHasWebsite, safety.SocialMetrics.WebsiteExists, SOURCE_SOCIAL)
+		facts.SetBool(analytics.FieldHasGitHub, safety.SocialMetrics.GitHubExists, SOURCE_SOCIAL)
+		facts.SetBool(analytics.FieldHasWhitepaper, safety.SocialMetrics.HasWhitepaper, SOURCE_SOCIAL)
+	}
+
+	if reason, unavailable := safety.Unavailable[types.CHECK_METADATA_MUTABILITY]; unavailable {
+		facts.SetUnknown(analytics.FieldMetadataMutable, reason, SOURCE_METADATA, failurePolicy(types.CHECK_METADATA_MUTABILITY))
+	} else {
+		facts.SetBool(analytics.FieldMetadataMutable, safety.MetadataMutable, SOURCE_METADATA)
+	}
+}
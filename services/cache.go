@@ -0,0 +1,306 @@
+package services
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"grind/logging"
+	"grind/types"
+)
+
+type CacheCheck string
+
+const (
+	CacheMetrics       CacheCheck = "metrics"
+	CacheLiquidityLock CacheCheck = "liquidity_lock"
+	CacheHoneypot      CacheCheck = "honeypot"
+	CacheHolders       CacheCheck = "holders"
+	CacheSocial        CacheCheck = "social"
+	CacheMetadata      CacheCheck = "metadata"
+
+	DEFAULT_CACHE_SIZE = 5000 // Maximum cached (mint, check) results
+
+	CACHE_PURGE_INTERVAL = time.Minute // How often expired entries are dropped
+)
+
+// DefaultCacheTTLs reflects how quickly each kind of data goes stale.
+var DefaultCacheTTLs = map[CacheCheck]time.Duration{
+	CacheMetrics:       1 * time.Minute,
+	CacheLiquidityLock: 30 * time.Minute,
+	CacheHoneypot:      1 * time.Hour,
+	CacheHolders:       2 * time.Minute,
+	CacheSocial:        6 * time.Hour,
+	CacheMetadata:      1 * time.Hour,
+}
+
+// CacheStore persists cache entries across restarts.
+type CacheStore interface {
+	LoadCacheEntries() ([]types.CacheEntry, error)
+	SaveCacheEntry(entry types.CacheEntry) error
+	PurgeCacheEntries(before time.Time) (int64, error)
+}
+
+type CacheCounter struct {
+	Hits   uint64
+	Misses uint64
+}
+
+type CacheStats struct {
+	Entries   int
+	Evictions uint64
+	Checks    map[CacheCheck]CacheCounter
+}
+
+type cacheItem struct {
+	key   string
+	entry types.CacheEntry
+}
+
+// TokenCache is a size-bounded LRU of per-mint lookup results with a TTL per check.
+type TokenCache struct {
+	mu         sync.Mutex
+	ttls       map[CacheCheck]time.Duration
+	maxEntries int
+	items      map[string]*list.Element
+	order      *list.List
+	store      CacheStore
+	counters   map[CacheCheck]*CacheCounter
+	evictions  uint64
+	lastPurge  time.Time
+}
+
+var tokenCache = NewTokenCache(DEFAULT_CACHE_SIZE, DefaultCacheTTLs)
+
+func NewTokenCache(maxEntries int, ttls map[CacheCheck]time.Duration) *TokenCache {
+	return &TokenCache{
+		ttls:       ttls,
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		counters:   make(map[CacheCheck]*CacheCounter),
+	}
+}
+
+// ConfigureCache changes the shared token cache's size and TTLs.
+func ConfigureCache(maxEntries int, ttls map[CacheCheck]time.Duration) {
+	tokenCache.SetLimits(maxEntries, ttls)
+}
+
+// SetLimits changes the cache's size and TTLs, evicting down to the new size.
+// Entries already cached keep their expiry.
+func (c *TokenCache) SetLimits(maxEntries int, ttls map[CacheCheck]time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = maxEntries
+	c.ttls = ttls
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// EnableCachePersistence backs the shared token cache with store and warms it
+// from the entries already persisted there.
+func EnableCachePersistence(store CacheStore) error {
+	return tokenCache.SetStore(store)
+}
+
+func (c *TokenCache) SetStore(store CacheStore) error {
+	entries, err := store.LoadCacheEntries()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store = store
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.ExpiresAt.After(now) {
+			c.insert(entry)
+		}
+	}
+	cacheLog.Info("Loaded cached token lookups", "entries", c.order.Len())
+	return nil
+}
+
+// Get decodes the cached result for (mint, check) into out, reporting whether it was fresh.
+func (c *TokenCache) Get(mint string, check CacheCheck, out any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter := c.counter(check)
+	elem, ok := c.items[cacheKey(mint, check)]
+	if !ok {
+		counter.Misses++
+		return false
+	}
+
+	item := elem.Value.(*cacheItem)
+	if time.Now().After(item.entry.ExpiresAt) {
+		c.remove(elem)
+		counter.Misses++
+		return false
+	}
+
+	if err := json.Unmarshal(item.entry.Value, out); err != nil {
+		c.remove(elem)
+		counter.Misses++
+		return false
+	}
+
+	c.order.MoveToFront(elem)
+	counter.Hits++
+	return true
+}
+
+// Fresh reports whether an unexpired result is cached, without counting a hit or miss.
+func (c *TokenCache) Fresh(mint string, check CacheCheck) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[cacheKey(mint, check)]
+	return ok && time.Now().Before(elem.Value.(*cacheItem).entry.ExpiresAt)
+}
+
+func (c *TokenCache) Set(mint string, check CacheCheck, value any) {
+	c.mu.Lock()
+	ttl, ok := c.ttls[check]
+	c.mu.Unlock()
+	if !ok || ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		cacheLog.Error("Failed to encode cache entry", "check", check, "mint", mint, logging.Err(err))
+		return
+	}
+
+	entry := types.CacheEntry{
+		Mint:      mint,
+		Check:     string(check),
+		Value:     data,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	c.mu.Lock()
+	c.insert(entry)
+	store := c.store
+	c.mu.Unlock()
+
+	if store != nil {
+		if err := store.SaveCacheEntry(entry); err != nil {
+			cacheLog.Error("Failed to persist cache entry", "check", check, "mint", mint, logging.Err(err))
+		}
+	}
+}
+
+func (c *TokenCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Entries:   c.order.Len(),
+		Evictions: c.evictions,
+		Checks:    make(map[CacheCheck]CacheCounter, len(c.counters)),
+	}
+	for check, counter := range c.counters {
+		stats.Checks[check] = *counter
+	}
+	return stats
+}
+
+// Purge drops expired entries from memory and from the store, at most once
+// per CACHE_PURGE_INTERVAL.
+func (c *TokenCache) Purge() {
+	c.mu.Lock()
+	now := time.Now()
+	if now.Sub(c.lastPurge) < CACHE_PURGE_INTERVAL {
+		c.mu.Unlock()
+		return
+	}
+	c.lastPurge = now
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if !now.Before(elem.Value.(*cacheItem).entry.ExpiresAt) {
+			c.remove(elem)
+		}
+		elem = prev
+	}
+	store := c.store
+	c.mu.Unlock()
+
+	if store != nil {
+		purged, err := store.PurgeCacheEntries(now)
+		if err != nil {
+			cacheLog.Error("Failed to purge persisted cache entries", logging.Err(err))
+			return
+		}
+		cacheLog.Debug("Purged expired cache entries", "rows", purged)
+	}
+}
+
+func (c *TokenCache) insert(entry types.CacheEntry) {
+	key := cacheKey(entry.Mint, CacheCheck(entry.Check))
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*cacheItem).entry = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheItem{key: key, entry: entry})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *TokenCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*cacheItem).key)
+}
+
+func (c *TokenCache) counter(check CacheCheck) *CacheCounter {
+	counter, ok := c.counters[check]
+	if !ok {
+		counter = &CacheCounter{}
+		c.counters[check] = counter
+	}
+	return counter
+}
+
+func cacheKey(mint string, check CacheCheck) string {
+	return string(check) + ":" + mint
+}
+
+// cachedLookup serves a lookup from the shared token cache, calling fetch and
+// caching its result on a miss. Failed lookups are never cached.
+func cachedLookup[T any](mint string, check CacheCheck, fetch func() (T, error)) (T, error) {
+	var value T
+	if tokenCache.Get(mint, check, &value) {
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	tokenCache.Set(mint, check, value)
+	return value, nil
+}
+
+// LogCacheStats purges expired entries from the shared token cache and logs
+// its counters; the scanner calls it once a cycle.
+func LogCacheStats() {
+	tokenCache.Purge()
+	stats := tokenCache.Stats()
+	cacheLog.Info("Token cache stats", "entries", stats.Entries, "evictions", stats.Evictions)
+	for check, counter := range stats.Checks {
+		cacheLog.Debug("Token cache check stats", "check", check, "hits", counter.Hits, "misses", counter.Misses)
+	}
+}
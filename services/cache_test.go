@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+type memoryCacheStore struct {
+	entries []types.CacheEntry
+	purged  []time.Time
+}
+
+func (s *memoryCacheStore) LoadCacheEntries() ([]types.CacheEntry, error) {
+	return s.entries, nil
+}
+
+func (s *memoryCacheStore) SaveCacheEntry(entry types.CacheEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryCacheStore) PurgeCacheEntries(before time.Time) (int64, error) {
+	s.purged = append(s.purged, before)
+	return 0, nil
+}
+
+func TestTokenCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewTokenCache(2, map[CacheCheck]time.Duration{CacheHoneypot: time.Hour})
+	cache.Set("a", CacheHoneypot, true)
+	cache.Set("b", CacheHoneypot, true)
+
+	var value bool
+	if !cache.Get("a", CacheHoneypot, &value) || !value {
+		t.Fatalf("a was not cached")
+	}
+	cache.Set("c", CacheHoneypot, false)
+
+	if cache.Fresh("b", CacheHoneypot) {
+		t.Errorf("b was kept over a, which was used more recently")
+	}
+	if !cache.Fresh("a", CacheHoneypot) || !cache.Fresh("c", CacheHoneypot) {
+		t.Errorf("a or c was evicted")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("stats = %+v, want 2 entries and 1 eviction", stats)
+	}
+}
+
+func TestTokenCacheSetLimits(t *testing.T) {
+	cache := NewTokenCache(3, map[CacheCheck]time.Duration{CacheHoneypot: time.Hour})
+	for _, mint := range []string{"a", "b", "c"} {
+		cache.Set(mint, CacheHoneypot, true)
+	}
+
+	cache.SetLimits(2, map[CacheCheck]time.Duration{CacheHoneypot: 0, CacheHolders: time.Hour})
+	if cache.Fresh("a", CacheHoneypot) || !cache.Fresh("c", CacheHoneypot) {
+		t.Errorf("shrinking the cache did not evict the least recently used entry")
+	}
+	cache.Set("d", CacheHoneypot, true)
+	cache.Set("d", CacheHolders, 12)
+	if cache.Fresh("d", CacheHoneypot) || !cache.Fresh("d", CacheHolders) {
+		t.Errorf("the new TTLs were not applied")
+	}
+}
+
+func TestTokenCacheExpiresByCheck(t *testing.T) {
+	cache := NewTokenCache(10, map[CacheCheck]time.Duration{CacheHolders: time.Hour})
+	cache.Set("a", CacheHolders, 12)
+	cache.Set("a", CacheSocial, "no ttl, never cached")
+	if cache.Fresh("a", CacheSocial) {
+		t.Errorf("cached a check without a TTL")
+	}
+
+	cache.items[cacheKey("a", CacheHolders)].Value.(*cacheItem).entry.ExpiresAt = time.Now().Add(-time.Second)
+	var holders int
+	if cache.Get("a", CacheHolders, &holders) {
+		t.Errorf("served an expired entry")
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expired entry still held: %+v", stats)
+	}
+}
+
+func TestTokenCacheCountsHitsAndMisses(t *testing.T) {
+	cache := NewTokenCache(10, DefaultCacheTTLs)
+	cache.Set("a", CacheMetrics, TokenMetrics{Liquidity: 5})
+
+	var metrics TokenMetrics
+	cache.Get("a", CacheMetrics, &metrics)
+	cache.Get("a", CacheMetrics, &metrics)
+	cache.Get("b", CacheMetrics, &metrics)
+	cache.Get("a", CacheHoneypot, new(bool))
+	cache.Fresh("a", CacheMetrics)
+
+	stats := cache.Stats()
+	if got := stats.Checks[CacheMetrics]; got != (CacheCounter{Hits: 2, Misses: 1}) {
+		t.Errorf("metrics counter = %+v, want 2 hits and 1 miss", got)
+	}
+	if got := stats.Checks[CacheHoneypot]; got != (CacheCounter{Misses: 1}) {
+		t.Errorf("honeypot counter = %+v, want 1 miss", got)
+	}
+	if metrics.Liquidity != 5 {
+		t.Errorf("decoded %+v", metrics)
+	}
+}
+
+func TestTokenCacheStoreWarmsAndPurges(t *testing.T) {
+	now := time.Now()
+	store := &memoryCacheStore{entries: []types.CacheEntry{
+		{Mint: "fresh", Check: string(CacheHoneypot), Value: []byte("true"), ExpiresAt: now.Add(time.Hour)},
+		{Mint: "stale", Check: string(CacheHoneypot), Value: []byte("true"), ExpiresAt: now.Add(-time.Hour)},
+	}}
+	cache := NewTokenCache(10, DefaultCacheTTLs)
+	if err := cache.SetStore(store); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.Fresh("fresh", CacheHoneypot) || cache.Fresh("stale", CacheHoneypot) {
+		t.Errorf("warmed with the wrong entries: %+v", cache.Stats())
+	}
+
+	cache.Set("new", CacheHoneypot, false)
+	if len(store.entries) != 3 {
+		t.Errorf("new entry not persisted")
+	}
+
+	cache.items[cacheKey("fresh", CacheHoneypot)].Value.(*cacheItem).entry.ExpiresAt = now.Add(-time.Second)
+	cache.Purge()
+	cache.Purge()
+	if len(store.purged) != 1 {
+		t.Errorf("store purged %d times, want once per interval", len(store.purged))
+	}
+	if stats := cache.Stats(); stats.Entries != 1 {
+		t.Errorf("entries after purge = %d, want 1", stats.Entries)
+	}
+}
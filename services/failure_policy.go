@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"grind/types"
+)
+
+// DefaultFailurePolicies decides, per check name, whether a failed data source
+// rejects the token. Checks not listed here fail closed.
+var DefaultFailurePolicies = map[string]types.CheckPolicy{
+	types.CHECK_LIQUIDITY_LOCK:      types.FailClosed,
+	types.CHECK_HONEYPOT:            types.FailClosed,
+	types.CHECK_TOP_HOLDER_SHARE:    types.FailClosed,
+	types.CHECK_HOLDER_COUNT:        types.FailClosed,
+	types.CHECK_SOCIAL_PRESENCE:     types.FailOpen,
+	types.CHECK_METADATA_MUTABILITY: types.FailOpen,
+}
+
+var (
+	failurePoliciesMu sync.RWMutex
+	failurePolicies   = copyPolicies(DefaultFailurePolicies)
+)
+
+// CheckFailurePolicies reports whether SetFailurePolicies would accept policies.
+func CheckFailurePolicies(policies map[string]types.CheckPolicy) error {
+	for name, policy := range policies {
+		if !types.IsSourcedCheck(name) {
+			return fmt.Errorf("unknown safety check %q, expected one of %s", name, strings.Join(types.SOURCED_CHECKS, ", "))
+		}
+		if policy != types.FailClosed && policy != types.FailOpen {
+			return fmt.Errorf("invalid failure policy %q for check %s", policy, name)
+		}
+	}
+	return nil
+}
+
+// SetFailurePolicies overrides the default policy for the given checks.
+func SetFailurePolicies(policies map[string]types.CheckPolicy) error {
+	if err := CheckFailurePolicies(policies); err != nil {
+		return err
+	}
+
+	failurePoliciesMu.Lock()
+	defer failurePoliciesMu.Unlock()
+
+	failurePolicies = copyPolicies(DefaultFailurePolicies)
+	for name, policy := range policies {
+		failurePolicies[name] = policy
+	}
+	return nil
+}
+
+func failurePolicy(name string) types.CheckPolicy {
+	failurePoliciesMu.RLock()
+	defer failurePoliciesMu.RUnlock()
+
+	if policy, ok := failurePolicies[name]; ok {
+		return policy
+	}
+	return types.FailClosed
+}
+
+func copyPolicies(policies map[string]types.CheckPolicy) map[string]types.CheckPolicy {
+	copied := make(map[string]types.CheckPolicy, len(policies))
+	for name, policy := range policies {
+		copied[name] = policy
+	}
+	return copied
+}
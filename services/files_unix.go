@@ -0,0 +1,29 @@
+//go:build unix
+
+package services
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an advisory lock on path, creating it if needed: exclusive
+// for writers, shared for readers. Call unlock to release it.
+func lockFile(path string, exclusive bool) (unlock func(), err error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(file.Fd()), how); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile takes a lock on path, creating it if needed: exclusive for
+// writers, shared for readers. Call unlock to release it.
+func lockFile(path string, exclusive bool) (unlock func(), err error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	// Lock the whole file, however long it gets
+	handle := windows.Handle(file.Fd())
+	overlapped := new(windows.Overlapped)
+	if err := windows.LockFileEx(handle, flags, 0, math.MaxUint32, math.MaxUint32, overlapped); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		windows.UnlockFileEx(handle, 0, math.MaxUint32, math.MaxUint32, overlapped)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"grind/logging"
+)
+
+const (
+	GOPLUS_TOKEN_SECURITY_URL = "https://api.gopluslabs.io/api/v1/token_security/solana"
+	DEFAULT_GOPLUS_BATCH_SIZE = 20 // Addresses per token_security request
+)
+
+type GoPlusLockInfo struct {
+	IsLocked     bool    `json:"is_locked"`
+	LockedAmount string  `json:"locked_amount"`
+	Percentage   float64 `json:"percentage"`
+	EndTime      string  `json:"end_time"`
+}
+
+// GoPlusTokenSecurity is the per-token entry of a token_security response.
+type GoPlusTokenSecurity struct {
+	IsSellable       string         `json:"is_sellable"`
+	SellTax          string         `json:"sell_tax"`
+	BuyTax           string         `json:"buy_tax"`
+	TransferPausable string         `json:"transfer_pausable"`
+	IsBlacklisted    string         `json:"is_blacklisted"`
+	IsProxy          string         `json:"is_proxy"`
+	IsHoneypot       string         `json:"is_honeypot"`
+	LockInfo         GoPlusLockInfo `json:"lock_info"`
+}
+
+type GoPlusResponse struct {
+	Code    int                            `json:"code"`
+	Message string                         `json:"message"`
+	Result  map[string]GoPlusTokenSecurity `json:"result"`
+}
+
+// GoPlusClient batches token_security lookups so that every check in a
+// TrackNewTokens cycle is served from a single response per batch.
+type GoPlusClient struct {
+	mu        sync.Mutex
+	baseURL   string
+	batchSize int
+	results   map[string]GoPlusTokenSecurity
+}
+
+var goPlus = NewGoPlusClient()
+
+func NewGoPlusClient() *GoPlusClient {
+	return &GoPlusClient{
+		baseURL:   GOPLUS_TOKEN_SECURITY_URL,
+		batchSize: DEFAULT_GOPLUS_BATCH_SIZE,
+		results:   make(map[string]GoPlusTokenSecurity),
+	}
+}
+
+// Prefetch looks up every address not already known, a batch at a time.
+func (c *GoPlusClient) Prefetch(addresses []string) error {
+	c.mu.Lock()
+	batchSize := c.batchSize
+	missing := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if _, ok := c.results[address]; !ok {
+			missing = append(missing, address)
+		}
+	}
+	c.mu.Unlock()
+
+	var lastErr error
+	for start := 0; start < len(missing); start += batchSize {
+		batch := missing[start:min(start+batchSize, len(missing))]
+		results, err := c.fetch(batch)
+		if err != nil {
+			safetyLog.Warn("GoPlus batch failed", "tokens", len(batch), logging.Err(err))
+			lastErr = err
+			continue
+		}
+
+		c.mu.Lock()
+		for address, security := range results {
+			c.results[address] = security
+		}
+		c.mu.Unlock()
+	}
+
+	return lastErr
+}
+
+// TokenSecurity returns the prefetched entry for address, fetching it on its own if needed.
+func (c *GoPlusClient) TokenSecurity(address string) (*GoPlusTokenSecurity, error) {
+	c.mu.Lock()
+	security, ok := c.results[address]
+	c.mu.Unlock()
+	if ok {
+		return &security, nil
+	}
+
+	results, err := c.fetch([]string{address})
+	if err != nil {
+		return nil, err
+	}
+	security, ok = results[address]
+	if !ok {
+		return nil, fmt.Errorf("token data not found in response")
+	}
+
+	c.mu.Lock()
+	c.results[address] = security
+	c.mu.Unlock()
+
+	return &security, nil
+}
+
+func (c *GoPlusClient) SetBaseURL(baseURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.baseURL = baseURL
+}
+
+// SetBatchSize changes how many addresses go in one request; sizes below one
+// keep the current size.
+func (c *GoPlusClient) SetBatchSize(size int) {
+	if size < 1 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batchSize = size
+}
+
+// Reset drops all prefetched results, forcing fresh lookups on the next cycle.
+func (c *GoPlusClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = make(map[string]GoPlusTokenSecurity)
+}
+
+func (c *GoPlusClient) fetch(addresses []string) (map[string]GoPlusTokenSecurity, error) {
+	c.mu.Lock()
+	baseURL := c.baseURL
+	c.mu.Unlock()
+
+	requestURL := fmt.Sprintf("%s?contract_addresses=%s", baseURL, url.QueryEscape(strings.Join(addresses, ",")))
+
+	resp, err := MakeGoPlusRequest(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch security info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result GoPlusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if result.Code != 1 {
+		return nil, fmt.Errorf("API error: %s", result.Message)
+	}
+
+	// Map entries back to the requested addresses, tolerating lowercased keys
+	securities := make(map[string]GoPlusTokenSecurity, len(addresses))
+	for _, address := range addresses {
+		if security, ok := result.Result[address]; ok {
+			securities[address] = security
+		} else if security, ok := result.Result[strings.ToLower(address)]; ok {
+			securities[address] = security
+		}
+	}
+
+	return securities, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// goPlusStub answers token_security requests, keying some entries in
+// lowercase as the API sometimes does, and records each request's addresses.
+type goPlusStub struct {
+	mu        sync.Mutex
+	batches   [][]string
+	lowercase map[string]bool
+}
+
+func newGoPlusStub(t *testing.T, lowercase ...string) (*GoPlusClient, *goPlusStub) {
+	t.Helper()
+	stub := &goPlusStub{lowercase: make(map[string]bool)}
+	for _, address := range lowercase {
+		stub.lowercase[address] = true
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addresses := strings.Split(r.URL.Query().Get("contract_addresses"), ",")
+		stub.mu.Lock()
+		stub.batches = append(stub.batches, addresses)
+		stub.mu.Unlock()
+
+		response := GoPlusResponse{Code: 1, Result: make(map[string]GoPlusTokenSecurity)}
+		for _, address := range addresses {
+			key := address
+			if stub.lowercase[address] {
+				key = strings.ToLower(address)
+			}
+			// The sell tax echoes the address so results can be matched to mints
+			response.Result[key] = GoPlusTokenSecurity{IsSellable: "1", SellTax: address}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewGoPlusClient()
+	client.SetBaseURL(server.URL)
+	return client, stub
+}
+
+func randomMints(t *testing.T, n int) []string {
+	t.Helper()
+	mints := make([]string, n)
+	for i := range mints {
+		key, err := solana.NewRandomPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mints[i] = key.PublicKey().String()
+	}
+	return mints
+}
+
+func TestGoPlusPrefetchBatches(t *testing.T) {
+	const batchSize = 8
+	client, stub := newGoPlusStub(t)
+	client.SetBatchSize(batchSize)
+	client.SetBatchSize(0) // Ignored
+	mints := randomMints(t, 2*batchSize+5)
+
+	if err := client.Prefetch(mints); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.batches) != 3 || len(stub.batches[0]) != batchSize ||
+		len(stub.batches[1]) != batchSize || len(stub.batches[2]) != 5 {
+		t.Fatalf("batch sizes: %d requests, want %d, %d and 5", len(stub.batches), batchSize, batchSize)
+	}
+
+	// Every mint is served from the prefetch, with its own entry
+	for _, mint := range mints {
+		security, err := client.TokenSecurity(mint)
+		if err != nil || security.SellTax != mint {
+			t.Errorf("%s: got %+v, %v", mint, security, err)
+		}
+	}
+	if len(stub.batches) != 3 {
+		t.Errorf("lookups after the prefetch made %d more requests", len(stub.batches)-3)
+	}
+
+	// Known mints are not asked for again
+	if err := client.Prefetch(mints[:3]); err != nil || len(stub.batches) != 3 {
+		t.Errorf("prefetching known mints made %d more requests, %v", len(stub.batches)-3, err)
+	}
+}
+
+func TestGoPlusLowercaseKeys(t *testing.T) {
+	mints := randomMints(t, 2)
+	client, _ := newGoPlusStub(t, mints[0])
+
+	if err := client.Prefetch(mints); err != nil {
+		t.Fatal(err)
+	}
+	for _, mint := range mints {
+		security, err := client.TokenSecurity(mint)
+		if err != nil || security.SellTax != mint {
+			t.Errorf("%s: got %+v, %v", mint, security, err)
+		}
+	}
+}
+
+func TestGoPlusReset(t *testing.T) {
+	client, stub := newGoPlusStub(t)
+	mint := randomMints(t, 1)[0]
+
+	client.Prefetch([]string{mint})
+	client.Reset()
+	if _, err := client.TokenSecurity(mint); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.batches) != 2 {
+		t.Errorf("got %d requests, want the lookup after Reset to fetch again", len(stub.batches))
+	}
+}
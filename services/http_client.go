@@ -2,9 +2,11 @@ package services
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"time"
+
+	"grind/logging"
+	"grind/metrics"
 )
 
 func MakeGoPlusRequest(url string) (*http.Response, error) {
@@ -14,14 +16,14 @@ func MakeGoPlusRequest(url string) (*http.Response, error) {
 	)
 
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout: currentSettings().RequestTimeout,
 	}
 
 	var lastErr error
 	for i := 0; i < MAX_RETRIES; i++ {
 		if i > 0 {
 			time.Sleep(RETRY_DELAY)
-			log.Printf("Retrying request (%d/%d)...", i+1, MAX_RETRIES)
+			httpLog.Debug("Retrying request", "url", url, "attempt", i+1, "of", MAX_RETRIES, logging.Err(lastErr))
 		}
 
 		req, err := http.NewRequest("GET", url, nil)
@@ -30,8 +32,10 @@ func MakeGoPlusRequest(url string) (*http.Response, error) {
 			continue
 		}
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
+			metrics.ObserveAPI(metrics.API_GOPLUS, start, err)
 			lastErr = err
 			continue
 		}
@@ -40,26 +44,18 @@ func MakeGoPlusRequest(url string) (*http.Response, error) {
 		if resp.StatusCode == http.StatusTooManyRequests {
 			resp.Body.Close()
 			lastErr = fmt.Errorf("rate limited")
+			metrics.ObserveAPI(metrics.API_GOPLUS, start, lastErr)
 			continue
 		}
 
+		var statusErr error
+		if resp.StatusCode >= http.StatusBadRequest {
+			statusErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		metrics.ObserveAPI(metrics.API_GOPLUS, start, statusErr)
+
 		return resp, nil
 	}
 
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
-
-type GoPlusResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    struct {
-		Solana struct {
-			LockInfo struct {
-				IsLocked     bool    `json:"is_locked"`
-				LockedAmount string  `json:"locked_amount"`
-				Percentage   float64 `json:"percentage"`
-				EndTime      string  `json:"end_time"`
-			} `json:"lock_info"`
-		} `json:"solana"`
-	} `json:"data"`
-}
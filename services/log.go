@@ -0,0 +1,26 @@
+package services
+
+import (
+	"log/slog"
+
+	"grind/logging"
+)
+
+var (
+	raydiumLog  = logging.For("raydium")
+	validateLog = logging.Sampled(raydiumLog)
+	monitorLog  = logging.For("monitor")
+	safetyLog   = logging.For("safety")
+	socialLog   = logging.For("social")
+	priceLog    = logging.For("prices")
+	trackerLog  = logging.For("tracker")
+	rugLog      = logging.For("rugwatch")
+	tradeLog    = logging.For("trading")
+	cacheLog    = logging.For("cache")
+	httpLog     = logging.For("http")
+)
+
+// pairLogger tags every record with the pair's mint, symbol and pool.
+func pairLogger(logger *slog.Logger, pair RaydiumPair) *slog.Logger {
+	return logger.With(logging.Token(pair.Address, pair.Symbol, pair.Pool.AmmId)...)
+}
@@ -3,10 +3,15 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
 	"runtime"
+	"sync"
 	"time"
 
+	"grind/events"
+	"grind/logging"
+	pipelineMetrics "grind/metrics"
+	"grind/types"
+
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/gagliardetto/solana-go/rpc/ws"
@@ -14,7 +19,7 @@ import (
 
 func MonitorMarket(targetToken solana.PublicKey) error {
 	// First connect
-	client, err := ws.Connect(context.Background(), rpc.MainNetBeta_WS)
+	client, err := ws.Connect(context.Background(), currentSettings().RPCWebsocketURL)
 	if err != nil {
 		return fmt.Errorf("failed to connect to websocket: %w", err)
 	}
@@ -38,7 +43,7 @@ func MonitorMarket(targetToken solana.PublicKey) error {
 			if result == nil {
 				return fmt.Errorf("received nil result")
 			}
-			log.Printf("Received program update: %+v\n", result)
+			monitorLog.Debug("Received program update", "slot", result.Context.Slot, "account", result.Value.Pubkey)
 		}
 	}
 }
@@ -53,9 +58,25 @@ func FetchPoolAccounts(ammId string) (*PoolAccounts, error) {
 	return accounts, nil
 }
 
+// poolAccountsCache holds pool accounts by AMM ID; a pool's vaults never move.
+var poolAccountsCache sync.Map
+
+// CachedPoolAccounts serves FetchPoolAccounts from memory.
+func CachedPoolAccounts(ammId string) (*PoolAccounts, error) {
+	if accounts, ok := poolAccountsCache.Load(ammId); ok {
+		return accounts.(*PoolAccounts), nil
+	}
+	accounts, err := FetchPoolAccounts(ammId)
+	if err != nil {
+		return nil, err
+	}
+	poolAccountsCache.Store(ammId, accounts)
+	return accounts, nil
+}
+
 func FetchFromBlockchain(ammId string) (*PoolAccounts, error) {
 	// Connect to Solana
-	client := rpc.New(rpc.MainNetBeta_RPC)
+	client := rpcClient()
 
 	// Get the AMM account data
 	ammPubKey := solana.MustPublicKeyFromBase58(ammId)
@@ -89,54 +110,55 @@ func FetchFromBlockchain(ammId string) (*PoolAccounts, error) {
 	}, nil
 }
 
-func TrackNewTokens(tokenChan chan<- RaydiumPair) {
-	log.Println("Starting trackNewTokens goroutine...")
-	seenTokens := make(map[string]time.Time)
-	tracker := NewTokenTracker("tracked_tokens.json")
+func TrackNewTokens(db Database, notifier Notifier, tracker *TokenTracker) {
+	monitorLog.Info("Starting token tracking")
 	// Start with a longer lookback period to catch more tokens initially
 	lastFetchTime := time.Now().Add(-24 * time.Hour)
 
 	for {
-		log.Printf("Starting new token fetch cycle... (lastFetchTime: %s)", lastFetchTime)
+		// Tokens listed while paused are picked up by the first cycle after resuming
+		if Paused() {
+			time.Sleep(currentSettings().FetchInterval)
+			continue
+		}
+
+		monitorLog.Debug("Starting fetch cycle", "since", lastFetchTime)
+		cycleStart := time.Now()
 		pairs, err := FetchRaydiumPairs()
 		if err != nil {
-			log.Printf("Error fetching pairs: %v\n", err)
-			time.Sleep(time.Second * FETCH_INTERVAL_SECONDS)
+			monitorLog.Error("Failed to fetch pairs", logging.Err(err))
+			time.Sleep(currentSettings().FetchInterval)
 			continue
 		}
 
-		log.Printf("Successfully fetched %d pairs from Raydium", len(pairs))
 		currentTime := time.Now()
 		skippedCount := 0
 
-		// Process each pair
+		// Collect candidates first so GoPlus lookups can be batched
+		candidates := make([]RaydiumPair, 0)
 		for _, pair := range pairs {
-			// Debug logging for each pair
-			log.Printf("Examining pair: %s (Address: %s, Timestamp: %s)",
-				pair.Symbol, pair.Address, pair.Timestamp)
-
-			// Skip invalid tokens with logging
+			// Skip invalid tokens
 			if pair.Address == "" || pair.Address == "11111111111111111111111111111111" {
 				skippedCount++
-				log.Printf("Skipping invalid token address: %s", pair.Symbol)
+				continue
+			}
+
+			// Tokens already evaluated, in this run or before a restart, are not evaluated again
+			if tracker.Seen(pair.Address) {
+				skippedCount++
 				continue
 			}
 
 			// Parse timestamp with better error handling
 			var pairTime time.Time
 			if pair.Timestamp == "" || pair.Timestamp == "-" {
-				if _, exists := seenTokens[pair.Address]; exists {
-					skippedCount++
-					log.Printf("Skipping previously seen token without timestamp: %s", pair.Symbol)
-					continue
-				}
 				pairTime = currentTime
-				log.Printf("New token found without timestamp: %s (%s)", pair.Symbol, pair.Address)
+				pairLogger(monitorLog, pair).Debug("New token without timestamp")
 			} else {
 				var err error
 				pairTime, err = time.Parse(time.RFC3339, pair.Timestamp)
 				if err != nil {
-					log.Printf("Failed to parse timestamp for token %s: %v", pair.Symbol, err)
+					pairLogger(monitorLog, pair).Warn("Failed to parse pair timestamp", "timestamp", pair.Timestamp, logging.Err(err))
 					continue
 				}
 			}
@@ -146,87 +168,128 @@ func TrackNewTokens(tokenChan chan<- RaydiumPair) {
 				continue
 			}
 
-			log.Printf("Processing potential new token: %s (%s)", pair.Symbol, pair.Address)
+			candidates = append(candidates, pair)
+		}
 
-			// Fetch metrics and safety data
-			metrics, err := FetchTokenMetrics(pair)
-			if err != nil {
-				log.Printf("Failed to fetch metrics for %s: %v", pair.Symbol, err)
+		// A config reload takes effect from the next cycle
+		analysis := CurrentAnalysis()
+
+		// One GoPlus token_security response per batch serves both lock and honeypot checks
+		goPlus.Reset()
+		addresses := make([]string, 0, len(candidates))
+		for _, pair := range candidates {
+			if tokenCache.Fresh(pair.Address, CacheLiquidityLock) && tokenCache.Fresh(pair.Address, CacheHoneypot) {
 				continue
 			}
+			addresses = append(addresses, pair.Address)
+		}
+		if err := goPlus.Prefetch(addresses); err != nil {
+			monitorLog.Warn("Failed to prefetch GoPlus security data", logging.Err(err))
+		}
+
+		// Process each candidate
+		for _, pair := range candidates {
+			if _, err := EnrichPairMetadata(&pair); err != nil {
+				pairLogger(monitorLog, pair).Warn("Failed to resolve metadata", logging.Err(err))
+			}
+			events.Publish(events.PAIR_DISCOVERED, pair.Address, pair.Symbol, pair)
+
+			logger := pairLogger(monitorLog, pair)
+			logger.Debug("Evaluating new token")
 
-			safety, err := CheckTokenSafety(pair.Address)
+			// Fetch metrics and safety data
+			metrics, err := CachedTokenMetrics(pair)
 			if err != nil {
-				log.Printf("Failed to check safety for %s: %v", pair.Symbol, err)
+				logger.Warn("Failed to fetch metrics", logging.Err(err))
 				continue
 			}
 
-			// Basic filtering with logging
-			if metrics.Liquidity < float64(MIN_LIQUIDITY_USD) {
-				log.Printf("Token %s skipped: insufficient liquidity (%.2f < %.2f)",
-					pair.Symbol, metrics.Liquidity, float64(MIN_LIQUIDITY_USD))
-				continue
+			safety := CheckTokenSafety(pair.Address)
+
+			// Keep the raw inputs so this decision can be replayed by the backtester
+			observation := types.TokenObservation{Pair: pair, Metrics: *metrics, Safety: safety, ObservedAt: currentTime}
+			if err := db.StoreObservation(observation); err != nil {
+				logger.Error("Failed to store observation", logging.Err(err))
 			}
-			if metrics.MarketCap > MAX_MARKET_CAP_USD {
-				log.Printf("Token %s skipped: market cap too high (%.2f > %.2f)",
-					pair.Symbol, metrics.MarketCap, MAX_MARKET_CAP_USD)
-				continue
+
+			// The analyzer's rules are the single filter; record its verdict either way
+			report := analysis.Evaluate(pair, *metrics, safety)
+			if err := db.StoreSafetyReport(*report); err != nil {
+				logger.Error("Failed to store safety report", logging.Err(err))
 			}
-			if safety.HolderCount < MIN_HOLDER_COUNT {
-				log.Printf("Token %s skipped: too few holders (%d < %d)",
-					pair.Symbol, safety.HolderCount, MIN_HOLDER_COUNT)
+			tracker.MarkSeen(pair.Address, currentTime)
+			recordVerdict(report)
+			events.Publish(events.SAFETY_REPORT, pair.Address, pair.Symbol, report)
+			if !report.Passed() {
+				logger.Info("Token rejected", "reasons", report.Summary())
+				events.Publish(events.FILTER_REJECTED, pair.Address, pair.Symbol, report.BlockingChecks())
 				continue
 			}
 
-			log.Printf("Token %s passed initial filters", pair.Symbol)
-			seenTokens[pair.Address] = currentTime
+			// Every model scores the same candidate so they can be compared later
+			scores := analysis.Score(pair, *metrics, safety)
+			if err := db.StoreScores(pair.Address, scores); err != nil {
+				logger.Error("Failed to store scores", logging.Err(err))
+			}
+			for _, score := range scores {
+				logger.Debug("Scored token", "model", score.Model, "score", score.Total)
+			}
+			events.Publish(events.FILTER_PASSED, pair.Address, pair.Symbol, scores)
 			tracker.Add(pair)
+			notifier.NotifySafetyReport(types.TokenReport{Pair: pair, Report: *report, Safety: safety, Scores: scores})
 
-			log.Printf("🔥 High potential token found: %s", pair.Symbol)
-			log.Printf("Metrics: Volume: $%.2f, Liquidity: $%.2f, Market Cap: $%.2f",
-				metrics.Volume24h, metrics.Liquidity, metrics.MarketCap)
-			log.Printf("Safety: Holders: %d, Top Holder Share: %.2f%%",
-				safety.HolderCount, safety.TopHolderShare*100)
-
-			select {
-			case tokenChan <- pair:
-				log.Printf("✅ Tracking new token: %s (%s)", pair.Name, pair.Address)
-			default:
-				log.Printf("⚠️ Channel full, skipping token: %s", pair.Name)
-			}
+			logger.Info("🔥 Token passed filters",
+				"volume24h", metrics.Volume24h, "liquidity", metrics.Liquidity, "marketCap", metrics.MarketCap,
+				"holders", safety.HolderCount, "topHolderShare", safety.TopHolderShare)
 		}
 
 		lastFetchTime = currentTime
-		// Add logging before sleep
-		log.Printf("Completed processing cycle, sleeping for %d seconds...", FETCH_INTERVAL_SECONDS)
+		tracker.Expire(currentTime)
+		if err := tracker.Save(); err != nil {
+			trackerLog.Error("Failed to save tracked tokens", logging.Err(err))
+		}
+		LogCacheStats()
+		pipelineMetrics.TrackedTokens.Set(float64(len(tracker.Tracked())))
+		pipelineMetrics.CycleDuration.Observe(time.Since(cycleStart).Seconds())
+		interval := currentSettings().FetchInterval
+		monitorLog.Info("Completed fetch cycle", "pairs", len(pairs), "candidates", len(candidates),
+			"skipped", skippedCount, "next", interval)
 		runtime.GC()
-		time.Sleep(time.Second * FETCH_INTERVAL_SECONDS)
+		time.Sleep(interval)
+	}
+}
+
+// recordVerdict counts a filter decision, and each blocking check behind a rejection.
+func recordVerdict(report *SafetyReport) {
+	pipelineMetrics.TokensEvaluated.Inc()
+	if report.Passed() {
+		pipelineMetrics.TokensPassed.Inc()
+		return
+	}
+	for _, check := range report.BlockingChecks() {
+		pipelineMetrics.TokensRejected.WithLabelValues(check.Name).Inc()
 	}
 }
 
 func HandleMarketActivity(activity *ws.ProgramResult) error {
-	// Log basic activity information
-	log.Printf("Market Activity Detected:")
-	log.Printf("- Slot: %d", activity.Context.Slot)
+	logger := monitorLog.With("slot", activity.Context.Slot, "account", activity.Value.Pubkey)
 
 	// Process account update
 	account := activity.Value
 	if account.Account != nil && account.Account.Owner != solana.SystemProgramID {
 		// Fetch account data
-		client := rpc.New(rpc.MainNetBeta_RPC)
+		client := rpcClient()
 		accountInfo, err := client.GetAccountInfo(
 			context.Background(),
 			account.Pubkey,
 		)
 		if err != nil {
-			log.Printf("Warning: Failed to fetch account info for %s: %v", account.Pubkey, err)
+			logger.Warn("Failed to fetch account info", logging.Err(err))
 			return err
 		}
 
-		// Log account changes
-		log.Printf("- Account Updated: %s", account.Pubkey)
-		log.Printf("  - Owner: %s", accountInfo.Value.Owner)
-		log.Printf("  - Data Size: %d bytes", len(accountInfo.Value.Data.GetBinary()))
+		logger.Info("Market account updated", "owner", accountInfo.Value.Owner,
+			"bytes", len(accountInfo.Value.Data.GetBinary()))
 	}
 
 	return nil
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const MAX_OFFCHAIN_METADATA_BYTES = 1 << 20 // Off-chain JSON is small; anything bigger is suspect
+
+// TokenMetadata is the on-chain Metaplex metadata account of a mint.
+type TokenMetadata struct {
+	UpdateAuthority      string
+	Mint                 string
+	Name                 string
+	Symbol               string
+	URI                  string
+	SellerFeeBasisPoints uint16
+	PrimarySaleHappened  bool
+	IsMutable            bool
+}
+
+// OffChainMetadata is the JSON document the metadata URI points at. Projects
+// put their links either under extensions or at the top level.
+type OffChainMetadata struct {
+	Name        string `json:"name"`
+	Symbol      string `json:"symbol"`
+	Description string `json:"description"`
+	ExternalURL string `json:"external_url"`
+	Website     string `json:"website"`
+	Twitter     string `json:"twitter"`
+	Telegram    string `json:"telegram"`
+	Extensions  struct {
+		Website  string `json:"website"`
+		Twitter  string `json:"twitter"`
+		Telegram string `json:"telegram"`
+		GitHub   string `json:"github"`
+	} `json:"extensions"`
+}
+
+func FetchTokenMetadata(mint string) (*TokenMetadata, error) {
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mint address: %w", err)
+	}
+
+	metadataAddress, _, err := solana.FindTokenMetadataAddress(mintKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive metadata address: %w", err)
+	}
+
+	client := rpcClient()
+	accountInfo, err := client.GetAccountInfo(context.Background(), metadataAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata account: %w", err)
+	}
+
+	return decodeTokenMetadata(accountInfo.Value.Data.GetBinary())
+}
+
+// ResolveTokenMetadata serves FetchTokenMetadata from the token cache.
+func ResolveTokenMetadata(mint string) (*TokenMetadata, error) {
+	return cachedLookup(mint, CacheMetadata, func() (*TokenMetadata, error) {
+		return FetchTokenMetadata(mint)
+	})
+}
+
+// EnrichPairMetadata fills in the name and symbol the pairs feed left blank,
+// and the metadata URI, from the mint's on-chain metadata.
+func EnrichPairMetadata(pair *RaydiumPair) (*TokenMetadata, error) {
+	metadata, err := ResolveTokenMetadata(pair.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if pair.Name == "" || pair.Name == "-" {
+		pair.Name = metadata.Name
+	}
+	if pair.Symbol == "" || pair.Symbol == "-" {
+		pair.Symbol = metadata.Symbol
+	}
+	pair.URI = metadata.URI
+
+	return metadata, nil
+}
+
+// decodeTokenMetadata reads the borsh-encoded Metaplex Metadata account:
+// key (u8), update authority, mint, name, symbol, uri, seller fee (u16),
+// optional creators, primary sale (bool) and is mutable (bool).
+func decodeTokenMetadata(data []byte) (*TokenMetadata, error) {
+	const (
+		updateAuthorityOffset = 1
+		mintOffset            = updateAuthorityOffset + 32
+		dataOffset            = mintOffset + 32
+		creatorSize           = 32 + 1 + 1 // address, verified, share
+	)
+
+	if len(data) < dataOffset {
+		return nil, fmt.Errorf("invalid metadata account size: %d", len(data))
+	}
+
+	reader := &borshReader{data: data, offset: dataOffset}
+	name, err := reader.string()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode name: %w", err)
+	}
+	symbol, err := reader.string()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode symbol: %w", err)
+	}
+	uri, err := reader.string()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode uri: %w", err)
+	}
+	sellerFee, err := reader.u16()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode seller fee: %w", err)
+	}
+
+	hasCreators, err := reader.bool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode creators: %w", err)
+	}
+	if hasCreators {
+		count, err := reader.u32()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode creators: %w", err)
+		}
+		if err := reader.skip(int(count) * creatorSize); err != nil {
+			return nil, fmt.Errorf("failed to decode creators: %w", err)
+		}
+	}
+
+	primarySale, err := reader.bool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode primary sale flag: %w", err)
+	}
+	isMutable, err := reader.bool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode mutability flag: %w", err)
+	}
+
+	return &TokenMetadata{
+		UpdateAuthority:      solana.PublicKeyFromBytes(data[updateAuthorityOffset:mintOffset]).String(),
+		Mint:                 solana.PublicKeyFromBytes(data[mintOffset:dataOffset]).String(),
+		Name:                 name,
+		Symbol:               symbol,
+		URI:                  uri,
+		SellerFeeBasisPoints: sellerFee,
+		PrimarySaleHappened:  primarySale,
+		IsMutable:            isMutable,
+	}, nil
+}
+
+func FetchOffChainMetadata(uri string) (*OffChainMetadata, error) {
+	if strings.HasPrefix(uri, "ipfs://") {
+		uri = "https://ipfs.io/ipfs/" + strings.TrimPrefix(uri, "ipfs://")
+	}
+
+	client := &http.Client{Timeout: currentSettings().RequestTimeout}
+	resp, err := client.Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch off-chain metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var metadata OffChainMetadata
+	if err := json.NewDecoder(io.LimitReader(resp.Body, MAX_OFFCHAIN_METADATA_BYTES)).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode off-chain metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+type borshReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *borshReader) skip(n int) error {
+	if n < 0 || r.offset+n > len(r.data) {
+		return io.ErrUnexpectedEOF
+	}
+	r.offset += n
+	return nil
+}
+
+func (r *borshReader) bool() (bool, error) {
+	if r.offset+1 > len(r.data) {
+		return false, io.ErrUnexpectedEOF
+	}
+	value := r.data[r.offset] != 0
+	r.offset++
+	return value, nil
+}
+
+func (r *borshReader) u16() (uint16, error) {
+	if r.offset+2 > len(r.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	value := binary.LittleEndian.Uint16(r.data[r.offset:])
+	r.offset += 2
+	return value, nil
+}
+
+func (r *borshReader) u32() (uint32, error) {
+	if r.offset+4 > len(r.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	value := binary.LittleEndian.Uint32(r.data[r.offset:])
+	r.offset += 4
+	return value, nil
+}
+
+// string reads a length-prefixed string, dropping the NUL padding Metaplex adds.
+func (r *borshReader) string() (string, error) {
+	length, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	if int(length) > len(r.data)-r.offset {
+		return "", io.ErrUnexpectedEOF
+	}
+	value := string(r.data[r.offset : r.offset+int(length)])
+	r.offset += int(length)
+	return strings.TrimRight(value, "\x00"), nil
+}
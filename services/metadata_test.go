@@ -0,0 +1,127 @@
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const sampleUpdateAuthority = "8BnEgHoWFysVcuFFX7QztDmzuH8r5ZFvyP3sYwn1XTh6"
+
+// metadataFixture borsh-encodes a Metaplex metadata account, padding the
+// strings with NULs the way the program does.
+func metadataFixture(creators int, mutable bool) []byte {
+	data := []byte{4} // Key::MetadataV1
+	data = append(data, solana.MustPublicKeyFromBase58(sampleUpdateAuthority).Bytes()...)
+	data = append(data, solana.MustPublicKeyFromBase58(sampleMint).Bytes()...)
+
+	for _, field := range []struct {
+		value string
+		size  int
+	}{
+		{"Popcat", 32},
+		{"POPCAT", 10},
+		{"https://arweave.net/popcat.json", 200},
+	} {
+		data = binary.LittleEndian.AppendUint32(data, uint32(field.size))
+		data = append(data, field.value+strings.Repeat("\x00", field.size-len(field.value))...)
+	}
+	data = binary.LittleEndian.AppendUint16(data, 250)
+
+	if creators > 0 {
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint32(data, uint32(creators))
+		for i := 0; i < creators; i++ {
+			data = append(data, make([]byte, 32)...)
+			data = append(data, 1, 100)
+		}
+	} else {
+		data = append(data, 0)
+	}
+
+	data = append(data, 1) // Primary sale happened
+	if mutable {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	// Fields after is_mutable are ignored
+	return append(data, 1, 255)
+}
+
+func TestDecodeTokenMetadata(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		creators int
+		mutable  bool
+	}{
+		{"no creators", 0, false},
+		{"two creators", 2, true},
+	} {
+		metadata, err := decodeTokenMetadata(metadataFixture(test.creators, test.mutable))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		want := TokenMetadata{
+			UpdateAuthority:      sampleUpdateAuthority,
+			Mint:                 sampleMint,
+			Name:                 "Popcat",
+			Symbol:               "POPCAT",
+			URI:                  "https://arweave.net/popcat.json",
+			SellerFeeBasisPoints: 250,
+			PrimarySaleHappened:  true,
+			IsMutable:            test.mutable,
+		}
+		if *metadata != want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", test.name, *metadata, want)
+		}
+	}
+}
+
+func TestDecodeTokenMetadataTruncated(t *testing.T) {
+	data := metadataFixture(2, true)
+	full := len(data) - 2 // Without the trailing fields the decoder ignores
+
+	for _, test := range []struct {
+		name   string
+		length int
+		want   string
+	}{
+		{"empty", 0, "invalid metadata account size"},
+		{"inside mint", 50, "invalid metadata account size"},
+		{"before name", 64, "invalid metadata account size"},
+		{"at name", 65, "name"},
+		{"inside name length", 67, "name"},
+		{"inside name", 80, "name"},
+		{"inside uri", 65 + 4 + 32 + 4 + 10 + 4 + 100, "uri"},
+		{"inside creators", full - 40, "creators"},
+		{"without mutability flag", full - 1, "mutability"},
+	} {
+		_, err := decodeTokenMetadata(data[:test.length])
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error about %s", test.name, err, test.want)
+		}
+		if test.length >= 65 && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: %v is not io.ErrUnexpectedEOF", test.name, err)
+		}
+	}
+}
+
+func TestDecodeTokenMetadataRejectsOversizedLengths(t *testing.T) {
+	data := metadataFixture(0, true)
+	binary.LittleEndian.PutUint32(data[65:], 1<<31)
+	if _, err := decodeTokenMetadata(data); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("name length past the end: got %v", err)
+	}
+
+	data = metadataFixture(1, true)
+	creatorsAt := 65 + 4 + 32 + 4 + 10 + 4 + 200 + 2 + 1
+	binary.LittleEndian.PutUint32(data[creatorsAt:], 1<<30)
+	if _, err := decodeTokenMetadata(data); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("creator count past the end: got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package services
+
+import "sync/atomic"
+
+// scanPaused stops TrackNewTokens evaluating new tokens. Tracking, price
+// collection and the rug watch carry on, so open positions stay guarded.
+var scanPaused atomic.Bool
+
+func SetPaused(paused bool) {
+	if scanPaused.Swap(paused) != paused {
+		monitorLog.Info("Token scanning changed", "paused", paused)
+	}
+}
+
+func Paused() bool {
+	return scanPaused.Load()
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"grind/events"
+	"grind/logging"
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// PositionBook holds the bot's open positions by mint. With a file path the
+// file is the record: it is re-read under a file lock before every change and
+// every lookup, so a running scanner and the command line tools see each
+// other's trades.
+type PositionBook struct {
+	mu        sync.Mutex
+	filepath  string
+	positions map[string]Position
+}
+
+// NewPositionBook keeps positions in filename, or only in memory if it is empty.
+func NewPositionBook(filename string) *PositionBook {
+	return &PositionBook{filepath: filename, positions: make(map[string]Position)}
+}
+
+// Load reads the positions file. A missing file is an empty book.
+func (b *PositionBook) Load() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sync(false, nil)
+}
+
+// sync re-reads the positions file under a file lock and, with change, applies
+// it and writes the book back before the lock is released. Callers hold b.mu.
+func (b *PositionBook) sync(exclusive bool, change func() bool) error {
+	apply := func() bool { return change != nil && change() }
+	if b.filepath == "" {
+		apply()
+		return nil
+	}
+
+	unlock, err := lockFile(b.filepath+".lock", exclusive)
+	if err != nil {
+		apply()
+		return fmt.Errorf("failed to lock positions file: %w", err)
+	}
+	defer unlock()
+
+	if err := b.read(); err != nil {
+		apply()
+		return err
+	}
+	if !apply() {
+		return nil
+	}
+	return b.save()
+}
+
+// read replaces the book with the file's contents. Callers hold b.mu and the file lock.
+func (b *PositionBook) read() error {
+	data, err := os.ReadFile(b.filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		b.positions = make(map[string]Position)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read positions file: %w", err)
+	}
+
+	var positions []Position
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return fmt.Errorf("failed to decode positions file: %w", err)
+	}
+	b.positions = make(map[string]Position, len(positions))
+	for _, position := range positions {
+		b.positions[position.Mint] = position
+	}
+	return nil
+}
+
+// save writes the book out. Callers hold b.mu and the file lock.
+func (b *PositionBook) save() error {
+	positions := make([]Position, 0, len(b.positions))
+	for _, position := range b.positions {
+		positions = append(positions, position)
+	}
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode positions: %w", err)
+	}
+	if err := writeFileAtomic(b.filepath, data); err != nil {
+		return fmt.Errorf("failed to save positions: %w", err)
+	}
+	return nil
+}
+
+// update applies change to the current positions file. A change that cannot
+// be read or written is still kept in memory and logged, since it records a
+// trade that happened.
+func (b *PositionBook) update(change func() bool) {
+	if err := b.sync(true, change); err != nil {
+		tradeLog.Error("Failed to update positions", "path", b.filepath, logging.Err(err))
+	}
+}
+
+// refresh picks up changes other processes made to the file. Callers hold b.mu.
+func (b *PositionBook) refresh() {
+	if err := b.sync(false, nil); err != nil {
+		tradeLog.Warn("Failed to re-read positions, using the last ones read", "path", b.filepath, logging.Err(err))
+	}
+}
+
+// Open records a buy, averaging the entry price into any existing position.
+func (b *PositionBook) Open(position Position) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.update(func() bool {
+		if existing, ok := b.positions[position.Mint]; ok {
+			total := existing.Amount + position.Amount
+			if total > 0 {
+				position.EntryPrice = (existing.Amount*existing.EntryPrice + position.Amount*position.EntryPrice) / total
+			}
+			position.Amount = total
+			position.OpenedAt = existing.OpenedAt
+		}
+		b.positions[position.Mint] = position
+		return true
+	})
+}
+
+func (b *PositionBook) Close(mint string) (Position, bool) {
+	b.mu.Lock()
+	var position Position
+	var ok bool
+	b.update(func() bool {
+		position, ok = b.positions[mint]
+		delete(b.positions, mint)
+		return ok
+	})
+	b.mu.Unlock()
+
+	if ok {
+		events.Publish(events.POSITION_CLOSED, position.Mint, position.Symbol, position)
+	}
+	return position, ok
+}
+
+// Reduce records a partial sell. Selling the whole amount or more closes the position.
+func (b *PositionBook) Reduce(mint string, amount float64) (Position, bool) {
+	b.mu.Lock()
+	var position Position
+	var ok, closed bool
+	b.update(func() bool {
+		position, ok = b.positions[mint]
+		if !ok {
+			return false
+		}
+		if amount < position.Amount {
+			position.Amount -= amount
+			b.positions[mint] = position
+		} else {
+			delete(b.positions, mint)
+			closed = true
+		}
+		return true
+	})
+	b.mu.Unlock()
+
+	if closed {
+		events.Publish(events.POSITION_CLOSED, position.Mint, position.Symbol, position)
+	}
+	return position, ok
+}
+
+func (b *PositionBook) Get(mint string) (Position, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+
+	position, ok := b.positions[mint]
+	return position, ok
+}
+
+func (b *PositionBook) All() []Position {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+
+	positions := make([]Position, 0, len(b.positions))
+	for _, position := range b.positions {
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// EmergencySeller dumps a whole position at any price, from the wallet
+// configured at the time of the exit.
+type EmergencySeller struct {
+	positions *PositionBook
+	notifier  Notifier
+}
+
+func NewEmergencySeller(positions *PositionBook, notifier Notifier) *EmergencySeller {
+	return &EmergencySeller{positions: positions, notifier: notifier}
+}
+
+// EmergencyExit sells the open position in pair, if there is one. The
+// position is closed once the sell is confirmed.
+func (s *EmergencySeller) EmergencyExit(pair RaydiumPair, reason string) error {
+	position, ok := s.positions.Get(pair.Address)
+	if !ok {
+		return nil
+	}
+
+	pairLogger(tradeLog, pair).Warn("Emergency sell", "amount", position.Amount, "reason", reason)
+	trade := Trade{Side: types.TradeSell, Mint: pair.Address, Symbol: pair.Symbol, Amount: position.Amount,
+		Reason: "emergency exit: " + reason}
+	var sig solana.Signature
+	wallet, err := GetWallet()
+	if err == nil {
+		// Getting out matters more than the price
+		sig, err = AttemptSell(wallet, pair, position.Amount, ANY_SLIPPAGE)
+	}
+	if err == nil {
+		trade.Signature = sig.String()
+		err = AwaitConfirmation(sig)
+	}
+	trade.Time = time.Now()
+	if err != nil {
+		trade.Error = err.Error()
+		s.notifier.NotifyTrade(trade)
+		return fmt.Errorf("failed to sell %s: %w", pair.Symbol, err)
+	}
+	s.notifier.NotifyTrade(trade)
+	s.positions.Close(pair.Address)
+	return nil
+}
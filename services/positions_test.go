@@ -0,0 +1,70 @@
+package services
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPositionBookSharesItsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+	daemon := NewPositionBook(path)
+	if err := daemon.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A command line buy made while the daemon runs
+	cli := NewPositionBook(path)
+	cli.Open(Position{Mint: sampleMint, Symbol: "POPCAT", Amount: 1000, EntryPrice: 0.5, OpenedAt: time.Now()})
+
+	if position, ok := daemon.Get(sampleMint); !ok || position.Amount != 1000 {
+		t.Fatalf("the daemon does not see the buy: %+v, %v", position, ok)
+	}
+
+	// The daemon's own change keeps the buy rather than writing over it
+	daemon.Open(Position{Mint: sampleMint, Symbol: "POPCAT", Amount: 1000, EntryPrice: 1.5})
+	if position, ok := cli.Get(sampleMint); !ok || position.Amount != 2000 || position.EntryPrice != 1 {
+		t.Errorf("after both buys got %+v, want 2000 at an average of 1", position)
+	}
+
+	if remaining, ok := cli.Reduce(sampleMint, 500); !ok || remaining.Amount != 1500 {
+		t.Errorf("reduce got %+v, %v", remaining, ok)
+	}
+	if _, ok := daemon.Close(sampleMint); !ok {
+		t.Errorf("the daemon could not close the reduced position")
+	}
+	if positions := cli.All(); len(positions) != 0 {
+		t.Errorf("positions left after closing: %+v", positions)
+	}
+}
+
+func TestPositionBookConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate books stand in for separate processes
+			NewPositionBook(path).Open(Position{Mint: sampleMint, Amount: 1, EntryPrice: 1})
+		}()
+	}
+	wg.Wait()
+
+	if position, ok := NewPositionBook(path).Get(sampleMint); !ok || position.Amount != 10 {
+		t.Errorf("got %+v, want every one of 10 buys", position)
+	}
+}
+
+func TestPositionBookInMemory(t *testing.T) {
+	book := NewPositionBook("")
+	book.Open(Position{Mint: sampleMint, Amount: 10})
+	if remaining, ok := book.Reduce(sampleMint, 4); !ok || remaining.Amount != 6 {
+		t.Errorf("reduce got %+v, %v", remaining, ok)
+	}
+	if _, ok := book.Reduce(sampleMint, 6); !ok || len(book.All()) != 0 {
+		t.Errorf("selling the rest left %+v", book.All())
+	}
+}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"grind/logging"
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	DEFAULT_PRICE_SAMPLE_SECONDS = types.DEFAULT_PRICE_SAMPLE_SECONDS
+
+	PRICE_SOURCE_PAIRS    = types.PRICE_SOURCE_PAIRS
+	PRICE_SOURCE_RESERVES = types.PRICE_SOURCE_RESERVES
+
+	// CANDIDATE_INTERVAL is the only bar kept for evaluated tokens that are
+	// not tracked; it is what backtests replay
+	CANDIDATE_INTERVAL = "1m"
+)
+
+type candleInterval struct {
+	Name     string
+	Duration time.Duration
+}
+
+// CANDLE_INTERVALS are the bar sizes every sample is aggregated into.
+var CANDLE_INTERVALS = []candleInterval{
+	{Name: "1m", Duration: time.Minute},
+	{Name: "5m", Duration: 5 * time.Minute},
+	{Name: "1h", Duration: time.Hour},
+}
+
+// PriceSource reads the current market of each tracked pair. Pairs it has no
+// reading for are left out of the result.
+type PriceSource interface {
+	Sample(pairs []RaydiumPair) ([]types.PriceSample, error)
+}
+
+// SampleListener is told about every sample the collector takes.
+type SampleListener interface {
+	ObserveSample(sample types.PriceSample)
+}
+
+// CandleStore is where aggregated candles are written, and read back to
+// resume a bar that was open when the scanner stopped.
+type CandleStore interface {
+	StoreCandle(candle types.Candle) error
+	LoadCandle(mint, interval string, openTime time.Time) (*types.Candle, error)
+}
+
+func NewPriceSource(name string) (PriceSource, error) {
+	switch name {
+	case "", PRICE_SOURCE_PAIRS:
+		return PairsFeedSource{}, nil
+	case PRICE_SOURCE_RESERVES:
+		return NewPoolReserveSource(), nil
+	default:
+		return nil, fmt.Errorf("unknown price source %q", name)
+	}
+}
+
+// PairsFeedSource reads USD price, liquidity and volume from the Raydium pairs
+// feed, reusing the scan cycle's download when it is recent enough. Samples
+// are timed by when the feed was read.
+type PairsFeedSource struct{}
+
+func (PairsFeedSource) Sample(pairs []RaydiumPair) ([]types.PriceSample, error) {
+	feed, fetchedAt, err := RecentRaydiumPairs(MAX_PAIRS_FEED_AGE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pairs: %w", err)
+	}
+
+	byAddress := make(map[string]RaydiumPair, len(feed))
+	for _, pair := range feed {
+		byAddress[pair.Address] = pair
+	}
+
+	samples := make([]types.PriceSample, 0, len(pairs))
+	for _, tracked := range pairs {
+		current, ok := byAddress[tracked.Address]
+		if !ok {
+			continue
+		}
+		samples = append(samples, types.PriceSample{
+			Mint:      tracked.Address,
+			Time:      fetchedAt,
+			Price:     current.Price,
+			Liquidity: current.Liquidity,
+			Volume24h: current.Volume24h,
+		})
+	}
+	return samples, nil
+}
+
+// PoolReserveSource prices each pair from its vault balances on-chain. Prices
+// and liquidity are in units of the quote token, and no volume is reported.
+type PoolReserveSource struct{}
+
+func NewPoolReserveSource() *PoolReserveSource {
+	return &PoolReserveSource{}
+}
+
+func (s *PoolReserveSource) Sample(pairs []RaydiumPair) ([]types.PriceSample, error) {
+	samples := make([]types.PriceSample, 0, len(pairs))
+	for _, pair := range pairs {
+		sample, err := s.samplePair(pair)
+		if err != nil {
+			pairLogger(priceLog, pair).Warn("Failed to sample pool reserves", logging.Err(err))
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+func (s *PoolReserveSource) samplePair(pair RaydiumPair) (types.PriceSample, error) {
+	if pair.Pool.AmmId == "" {
+		return types.PriceSample{}, fmt.Errorf("pair has no AMM ID")
+	}
+
+	accounts, err := CachedPoolAccounts(pair.Pool.AmmId)
+	if err != nil {
+		return types.PriceSample{}, fmt.Errorf("failed to fetch pool accounts: %w", err)
+	}
+
+	base, err := s.vaultBalance(accounts.BaseVault)
+	if err != nil {
+		return types.PriceSample{}, fmt.Errorf("failed to read base vault: %w", err)
+	}
+	quote, err := s.vaultBalance(accounts.QuoteVault)
+	if err != nil {
+		return types.PriceSample{}, fmt.Errorf("failed to read quote vault: %w", err)
+	}
+	// A drained pool has no price, but its zero liquidity is still worth reporting
+	if base == 0 || quote == 0 {
+		return types.PriceSample{Mint: pair.Address, Time: time.Now()}, nil
+	}
+
+	// The tracked mint may sit on either side of the pool
+	price, liquidity := quote/base, 2*quote
+	if pair.Pool.QuoteMint == pair.Address {
+		price, liquidity = base/quote, 2*base
+	}
+
+	return types.PriceSample{
+		Mint:      pair.Address,
+		Time:      time.Now(),
+		Price:     price,
+		Liquidity: liquidity,
+	}, nil
+}
+
+func (s *PoolReserveSource) vaultBalance(vault solana.PublicKey) (float64, error) {
+	return TokenAccountBalance(rpcClient(), vault)
+}
+
+// TokenAccountBalance reads a token account's balance in whole tokens.
+func TokenAccountBalance(client *rpc.Client, account solana.PublicKey) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := client.GetTokenAccountBalance(ctx, account, rpc.CommitmentConfirmed)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil || result.Value == nil {
+		return 0, fmt.Errorf("empty balance response")
+	}
+	return strconv.ParseFloat(result.Value.UiAmountString, 64)
+}
+
+// CandleAggregator folds price samples into OHLCV candles for every interval
+// in CANDLE_INTERVALS.
+type CandleAggregator struct {
+	open       map[string]*types.Candle // By mint and interval
+	lastVolume map[string]float64       // Last rolling 24h volume seen per mint
+	store      CandleStore              // Optional, to resume stored bars
+}
+
+// NewCandleAggregator starts with no open bars. With a store, the first
+// sample of a bar that already has a stored candle, such as the one open when
+// the scanner restarted, continues that candle rather than replacing it.
+func NewCandleAggregator(store CandleStore) *CandleAggregator {
+	return &CandleAggregator{
+		open:       make(map[string]*types.Candle),
+		lastVolume: make(map[string]float64),
+		store:      store,
+	}
+}
+
+// Add folds sample into the open candle of each interval, starting a new one
+// when the sample falls past the end of the current bar, and returns the
+// candles it touched. The rolling 24h volume only yields a per-bar volume
+// through its increases between samples, so the first sample adds none.
+func (a *CandleAggregator) Add(sample types.PriceSample) []types.Candle {
+	volume := 0.0
+	if previous, ok := a.lastVolume[sample.Mint]; ok && sample.Volume24h > previous {
+		volume = sample.Volume24h - previous
+	}
+	a.lastVolume[sample.Mint] = sample.Volume24h
+
+	candles := make([]types.Candle, 0, len(CANDLE_INTERVALS))
+	for _, interval := range CANDLE_INTERVALS {
+		key := sample.Mint + "/" + interval.Name
+		openTime := sample.Time.Truncate(interval.Duration)
+
+		candle, ok := a.open[key]
+		if !ok {
+			candle = a.resume(sample.Mint, interval.Name, openTime)
+		}
+		if candle == nil || !candle.OpenTime.Equal(openTime) {
+			candle = &types.Candle{
+				Mint:     sample.Mint,
+				Interval: interval.Name,
+				OpenTime: openTime,
+				Open:     sample.Price,
+				High:     sample.Price,
+				Low:      sample.Price,
+			}
+			a.open[key] = candle
+		}
+
+		candle.High = max(candle.High, sample.Price)
+		candle.Low = min(candle.Low, sample.Price)
+		candle.Close = sample.Price
+		candle.Volume += volume
+		candle.Liquidity = sample.Liquidity
+		candle.Samples++
+
+		candles = append(candles, *candle)
+	}
+	return candles
+}
+
+// resume returns the stored candle of the bar, if any.
+func (a *CandleAggregator) resume(mint, interval string, openTime time.Time) *types.Candle {
+	if a.store == nil {
+		return nil
+	}
+	candle, err := a.store.LoadCandle(mint, interval, openTime)
+	if err != nil {
+		priceLog.Warn("Failed to load stored candle", "interval", interval, "mint", mint, logging.Err(err))
+		return nil
+	}
+	return candle
+}
+
+// Retain drops the state of every mint not in mints.
+func (a *CandleAggregator) Retain(mints map[string]bool) {
+	for key, candle := range a.open {
+		if !mints[candle.Mint] {
+			delete(a.open, key)
+		}
+	}
+	for mint := range a.lastVolume {
+		if !mints[mint] {
+			delete(a.lastVolume, mint)
+		}
+	}
+}
+
+// PriceCollector samples every tracked mint at a fixed cadence and keeps its
+// candles up to date in the store. Open candles are rewritten on each sample
+// so charts see the current bar. Rejected candidates are sampled from the
+// pairs feed too, into 1m candles only, so a backtest of a looser config has
+// prices for what it would have let through.
+type PriceCollector struct {
+	tracker    *TokenTracker
+	source     PriceSource
+	candidates PriceSource
+	store      CandleStore
+	cadence    time.Duration
+	aggregator *CandleAggregator
+	listeners  []SampleListener
+}
+
+func NewPriceCollector(tracker *TokenTracker, source PriceSource, store CandleStore, cadence time.Duration) *PriceCollector {
+	if cadence <= 0 {
+		cadence = DEFAULT_PRICE_SAMPLE_SECONDS * time.Second
+	}
+	return &PriceCollector{
+		tracker:    tracker,
+		source:     source,
+		candidates: PairsFeedSource{},
+		store:      store,
+		cadence:    cadence,
+		aggregator: NewCandleAggregator(store),
+	}
+}
+
+// AddListener registers l for every sample of a tracked mint, including ones
+// without a price.
+func (c *PriceCollector) AddListener(l SampleListener) {
+	c.listeners = append(c.listeners, l)
+}
+
+func (c *PriceCollector) Run(ctx context.Context) {
+	priceLog.Info("Starting price collector", "every", c.cadence)
+	ticker := time.NewTicker(c.cadence)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.collect()
+		}
+	}
+}
+
+func (c *PriceCollector) collect() {
+	pairs := c.tracker.Tracked()
+
+	mints := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		mints[pair.Address] = true
+	}
+	candidates := make([]RaydiumPair, 0)
+	for _, mint := range c.tracker.Evaluated() {
+		if !mints[mint] {
+			candidates = append(candidates, RaydiumPair{Address: mint})
+		}
+	}
+	tracked := len(mints)
+	for _, pair := range candidates {
+		mints[pair.Address] = true
+	}
+	c.aggregator.Retain(mints)
+
+	stored := 0
+	if len(pairs) > 0 {
+		samples, err := c.source.Sample(pairs)
+		if err != nil {
+			priceLog.Error("Failed to sample prices", logging.Err(err))
+		}
+		for _, sample := range samples {
+			for _, listener := range c.listeners {
+				listener.ObserveSample(sample)
+			}
+			stored += c.record(sample, true)
+		}
+	}
+	if len(candidates) > 0 {
+		samples, err := c.candidates.Sample(candidates)
+		if err != nil {
+			priceLog.Error("Failed to sample candidate prices", logging.Err(err))
+		}
+		for _, sample := range samples {
+			stored += c.record(sample, false)
+		}
+	}
+	priceLog.Debug("Sampled tokens", "tracked", tracked, "candidates", len(candidates), "candles", stored)
+}
+
+// record folds sample into its candles and stores them, every interval for a
+// tracked mint and CANDIDATE_INTERVAL otherwise. It returns how many it stored.
+func (c *PriceCollector) record(sample types.PriceSample, tracked bool) int {
+	if sample.Price <= 0 {
+		return 0
+	}
+	stored := 0
+	for _, candle := range c.aggregator.Add(sample) {
+		if !tracked && candle.Interval != CANDIDATE_INTERVAL {
+			continue
+		}
+		if err := c.store.StoreCandle(candle); err != nil {
+			priceLog.Error("Failed to store candle", "interval", candle.Interval, "mint", candle.Mint, logging.Err(err))
+			continue
+		}
+		stored++
+	}
+	return stored
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"grind/types"
+)
+
+type memoryCandleStore struct {
+	candles map[string]types.Candle
+}
+
+func candleKey(mint, interval string, openTime time.Time) string {
+	return mint + "/" + interval + "/" + openTime.UTC().Format(time.RFC3339)
+}
+
+func (s *memoryCandleStore) StoreCandle(candle types.Candle) error {
+	s.candles[candleKey(candle.Mint, candle.Interval, candle.OpenTime)] = candle
+	return nil
+}
+
+func (s *memoryCandleStore) LoadCandle(mint, interval string, openTime time.Time) (*types.Candle, error) {
+	candle, ok := s.candles[candleKey(mint, interval, openTime)]
+	if !ok {
+		return nil, nil
+	}
+	return &candle, nil
+}
+
+func TestCandleAggregator(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	aggregator := NewCandleAggregator(nil)
+
+	var candles []types.Candle
+	for i, sample := range []struct {
+		offset time.Duration
+		price  float64
+		volume float64
+	}{
+		{10 * time.Second, 1.0, 1000},
+		{30 * time.Second, 1.5, 1200},
+		{50 * time.Second, 0.8, 1100},
+		{70 * time.Second, 1.1, 1400},
+	} {
+		candles = aggregator.Add(types.PriceSample{Mint: "mint", Time: base.Add(sample.offset), Price: sample.price, Volume24h: sample.volume})
+		if len(candles) != len(CANDLE_INTERVALS) {
+			t.Fatalf("sample %d touched %d candles", i, len(candles))
+		}
+		if i == 2 {
+			want := types.Candle{Mint: "mint", Interval: "1m", OpenTime: base, Open: 1, High: 1.5, Low: 0.8, Close: 0.8, Volume: 200, Samples: 3}
+			if candles[0] != want {
+				t.Errorf("first minute:\ngot  %+v\nwant %+v", candles[0], want)
+			}
+		}
+	}
+
+	next := types.Candle{Mint: "mint", Interval: "1m", OpenTime: base.Add(time.Minute), Open: 1.1, High: 1.1, Low: 1.1, Close: 1.1, Volume: 300, Samples: 1}
+	if candles[0] != next {
+		t.Errorf("second minute:\ngot  %+v\nwant %+v", candles[0], next)
+	}
+	if five := candles[1]; five.Open != 1 || five.High != 1.5 || five.Low != 0.8 || five.Samples != 4 || five.Volume != 500 {
+		t.Errorf("5m bar = %+v", five)
+	}
+}
+
+func TestCandleAggregatorResumesStoredBar(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryCandleStore{candles: make(map[string]types.Candle)}
+	stored := types.Candle{Mint: "mint", Interval: "1m", OpenTime: base, Open: 1, High: 1.6, Low: 0.9, Close: 1.2, Volume: 400, Samples: 5}
+	store.StoreCandle(stored)
+
+	// A restarted scanner's first sample falls in the bar it left open
+	aggregator := NewCandleAggregator(store)
+	candles := aggregator.Add(types.PriceSample{Mint: "mint", Time: base.Add(40 * time.Second), Price: 1.7, Volume24h: 9000})
+
+	want := types.Candle{Mint: "mint", Interval: "1m", OpenTime: base, Open: 1, High: 1.7, Low: 0.9, Close: 1.7, Volume: 400, Samples: 6}
+	if candles[0] != want {
+		t.Errorf("got  %+v\nwant %+v", candles[0], want)
+	}
+	if candles[1].Open != 1.7 || candles[1].Samples != 1 {
+		t.Errorf("5m bar without a stored candle = %+v, want a fresh one", candles[1])
+	}
+}
@@ -6,59 +6,94 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
+	"grind/logging"
+	"grind/metrics"
+
 	"github.com/gagliardetto/solana-go"
 )
 
+// MAX_PAIRS_FEED_AGE is how old a fetched pairs feed may be and still be
+// reused instead of downloading it again.
+const MAX_PAIRS_FEED_AGE = 30 * time.Second
+
+var (
+	feedMu        sync.Mutex
+	lastFeed      RaydiumResponse
+	lastFeedFetch time.Time
+)
+
+func rememberFeed(pairs RaydiumResponse) {
+	feedMu.Lock()
+	lastFeed, lastFeedFetch = pairs, time.Now()
+	feedMu.Unlock()
+}
+
+// RecentRaydiumPairs returns the last fetched pairs feed if it is younger than
+// maxAge, such as the one the scan cycle just read, and fetches it otherwise.
+// It also returns when the feed was read.
+func RecentRaydiumPairs(maxAge time.Duration) (RaydiumResponse, time.Time, error) {
+	feedMu.Lock()
+	pairs, fetchedAt := lastFeed, lastFeedFetch
+	feedMu.Unlock()
+	if pairs != nil && time.Since(fetchedAt) < maxAge {
+		return pairs, fetchedAt, nil
+	}
+
+	pairs, err := FetchRaydiumPairs()
+	return pairs, time.Now(), err
+}
+
+// IsValidPair runs for every pair in the feed, tens of thousands per cycle,
+// so it logs through the sampled logger at debug level.
 func IsValidPair(pair RaydiumPair) bool {
-	// Log the full pair data for debugging
-	log.Printf("Checking pair: %+v", pair)
+	logger := validateLog
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		logger = pairLogger(logger, pair)
+	}
 
 	// Check for Market field as an alternative to AmmId
 	if pair.Market != "" {
 		// If we have a valid market, check for either base or quote mint
 		if pair.Pool.BaseMint != "" || pair.Pool.QuoteMint != "" {
-			log.Printf("Valid pair found (using Market): %s (Market: %s, BaseMint: %s, QuoteMint: %s)",
-				pair.Name, pair.Market, pair.Pool.BaseMint, pair.Pool.QuoteMint)
+			logger.Debug("Valid pair", "by", "market", "market", pair.Market,
+				"baseMint", pair.Pool.BaseMint, "quoteMint", pair.Pool.QuoteMint)
 			return true
 		}
 	}
 
 	// Check for non-zero liquidity as another validity indicator
 	if pair.Liquidity > 0 {
-		log.Printf("Valid pair found (using Liquidity): %s (Liquidity: %.2f)",
-			pair.Name, pair.Liquidity)
+		logger.Debug("Valid pair", "by", "liquidity", "liquidity", pair.Liquidity)
 		return true
 	}
 
 	// Check for valid price
 	if pair.Price > 0 {
-		log.Printf("Valid pair found (using Price): %s (Price: %.8f)",
-			pair.Name, pair.Price)
+		logger.Debug("Valid pair", "by", "price", "price", pair.Price)
 		return true
 	}
 
 	// If we have both base and quote mints, consider it valid
 	if pair.Pool.BaseMint != "" && pair.Pool.QuoteMint != "" {
-		log.Printf("Valid pair found (using Mints): %s (BaseMint: %s, QuoteMint: %s)",
-			pair.Name, pair.Pool.BaseMint, pair.Pool.QuoteMint)
+		logger.Debug("Valid pair", "by", "mints", "baseMint", pair.Pool.BaseMint, "quoteMint", pair.Pool.QuoteMint)
 		return true
 	}
 
-	// Log why the pair was invalid
-	log.Printf("Invalid pair: %s - No valid identifiers found (Market: %s, Liquidity: %.2f, Price: %.8f, BaseMint: %s, QuoteMint: %s)",
-		pair.Name, pair.Market, pair.Liquidity, pair.Price, pair.Pool.BaseMint, pair.Pool.QuoteMint)
+	logger.Debug("Invalid pair, no valid identifiers", "name", pair.Name, "market", pair.Market,
+		"liquidity", pair.Liquidity, "price", pair.Price)
 	return false
 }
 
 type RaydiumResponse []RaydiumPair
 
 func FetchRaydiumPairs() ([]RaydiumPair, error) {
-	log.Println("Fetching Raydium pairs...")
+	raydiumLog.Debug("Fetching Raydium pairs")
 
 	// Increase timeouts even further and optimize transport settings
 	client := &http.Client{
@@ -84,13 +119,13 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
-			log.Printf("Retry attempt %d/%d after error: %v", attempt+1, maxRetries, lastErr)
+			raydiumLog.Warn("Retrying pairs fetch", "attempt", attempt+1, "of", maxRetries, logging.Err(lastErr))
 			time.Sleep(time.Second * time.Duration(attempt+1) * 2) // Increased backoff
 		}
 
 		// Create request with context
 		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
-		req, err := http.NewRequestWithContext(ctx, "GET", "https://api.raydium.io/v2/main/pairs", nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", currentSettings().RaydiumPairsURL, nil)
 		if err != nil {
 			cancel()
 			lastErr = fmt.Errorf("failed to create request: %w", err)
@@ -101,14 +136,16 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 		req.Header.Add("Accept-Encoding", "gzip")
 		req.Header.Add("User-Agent", "Mozilla/5.0")
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
 			cancel()
+			metrics.ObserveAPI(metrics.API_RAYDIUM_PAIRS, start, err)
 			lastErr = fmt.Errorf("failed to fetch pairs: %w", err)
 			continue
 		}
 
-		log.Printf("Response status: %d", resp.StatusCode)
+		raydiumLog.Debug("Pairs response", "status", resp.StatusCode)
 
 		// Create a buffer to efficiently read the response
 		var body []byte
@@ -117,6 +154,7 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 			if err != nil {
 				resp.Body.Close()
 				cancel()
+				metrics.ObserveAPI(metrics.API_RAYDIUM_PAIRS, start, err)
 				lastErr = fmt.Errorf("failed to create gzip reader: %w", err)
 				continue
 			}
@@ -125,6 +163,7 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 				reader.Close()
 				resp.Body.Close()
 				cancel()
+				metrics.ObserveAPI(metrics.API_RAYDIUM_PAIRS, start, err)
 				lastErr = fmt.Errorf("failed to read gzipped response: %w", err)
 				continue
 			}
@@ -134,31 +173,22 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 		}
 		resp.Body.Close()
 		cancel()
+		metrics.ObserveAPI(metrics.API_RAYDIUM_PAIRS, start, err)
 
 		if err != nil {
 			lastErr = fmt.Errorf("failed to read response body: %w", err)
 			continue
 		}
 
-		log.Printf("Successfully read %d bytes from response", len(body))
-
-		// Add sample logging before parsing
-		log.Printf("Sample of raw response body: %s", string(body[:min(1000, len(body))]))
-
 		var pairs RaydiumResponse
 		if err := json.Unmarshal(body, &pairs); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal response (error: %w), raw data sample: %s",
 				err, string(body[:min(1000, len(body))]))
 		}
-
-		// Log the first few pairs before validation
-		log.Printf("First pair before validation: %+v", pairs[0])
-
-		// Log a sample of the first valid and invalid pairs
-		log.Printf("First 3 pairs from response:")
-		for i := 0; i < min(3, len(pairs)); i++ {
-			log.Printf("Pair %d: %+v", i+1, pairs[i])
+		if len(pairs) == 0 {
+			return nil, fmt.Errorf("pairs feed returned no pairs")
 		}
+		raydiumLog.Debug("Read pairs feed", "bytes", len(body), "pairs", len(pairs))
 
 		// Modified validation logic with better counting
 		validPairs := make(RaydiumResponse, 0)
@@ -169,24 +199,18 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 			if IsValidPair(pair) {
 				validPairs = append(validPairs, pair)
 				validCount++
-				if validCount <= 3 {
-					log.Printf("Sample valid pair %d: %+v", validCount, pair)
-				}
 			} else {
 				invalidCount++
-				if invalidCount <= 3 {
-					log.Printf("Sample invalid pair %d: %+v", invalidCount, pair)
-				}
 			}
 		}
 
-		log.Printf("Validation Results:")
-		log.Printf("- Total pairs processed: %d", len(pairs))
-		log.Printf("- Valid pairs: %d", validCount)
-		log.Printf("- Invalid pairs: %d", invalidCount)
+		raydiumLog.Info("Validated pairs feed", "total", len(pairs), "valid", validCount, "invalid", invalidCount)
+		metrics.PairsFetched.WithLabelValues("valid").Set(float64(validCount))
+		metrics.PairsFetched.WithLabelValues("invalid").Set(float64(invalidCount))
 
 		// Return valid pairs if we have any
 		if validCount > 0 {
+			rememberFeed(validPairs)
 			return validPairs, nil
 		}
 
@@ -197,44 +221,11 @@ func FetchRaydiumPairs() ([]RaydiumPair, error) {
 	return nil, fmt.Errorf("max retries exceeded, last error: %v", lastErr)
 }
 
-func ProcessNewTokens(ctx context.Context, tokenChan chan<- RaydiumPair, db Database, notifier Notifier) {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			pairs, err := FetchRaydiumPairs()
-			if err != nil {
-				log.Printf("Error fetching Raydium pairs: %v", err)
-				continue
-			}
-
-			for _, pair := range pairs {
-				if IsValidPair(pair) {
-					select {
-					case <-ctx.Done():
-						return
-					case tokenChan <- pair:
-						// Optional: Add notification or database logging
-						if err := db.StorePair(pair); err != nil {
-							log.Printf("Error storing pair: %v", err)
-						}
-						notifier.NotifyNewPair(pair)
-					}
-				}
-			}
-		}
-	}
-}
-
 func FetchFromRaydiumAPI(ammId string) (*PoolAccounts, error) {
 	// Raydium's API endpoint for pool info
-	url := fmt.Sprintf("https://api.raydium.io/v2/main/pool/%s", ammId)
+	url := fmt.Sprintf("%s/%s", currentSettings().RaydiumPoolURL, ammId)
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Timeout: currentSettings().RequestTimeout}
 	resp, err := client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pool info: %w", err)
@@ -262,6 +253,21 @@ func FetchFromRaydiumAPI(ammId string) (*PoolAccounts, error) {
 	}, nil
 }
 
+// FindPair looks the mint up in the pairs feed, reusing one fetched within
+// MAX_PAIRS_FEED_AGE.
+func FindPair(mint string) (RaydiumPair, bool, error) {
+	pairs, _, err := RecentRaydiumPairs(MAX_PAIRS_FEED_AGE)
+	if err != nil {
+		return RaydiumPair{}, false, err
+	}
+	for _, pair := range pairs {
+		if pair.Address == mint {
+			return pair, true, nil
+		}
+	}
+	return RaydiumPair{}, false, nil
+}
+
 func FetchPoolInfo(tokenMint string) (*RaydiumPool, error) {
 	pairs, err := FetchRaydiumPairs()
 	if err != nil {
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"grind/events"
+	"grind/logging"
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	ALERT_LIQUIDITY_REMOVED = "liquidity_removed"
+	ALERT_HOLDER_DUMP       = "holder_dump"
+	ALERT_DEV_SELL          = "dev_sell"
+	ALERT_SUPPLY_INFLATION  = "supply_inflation"
+)
+
+// PositionExiter closes whatever is held in a pair.
+type PositionExiter interface {
+	EmergencyExit(pair RaydiumPair, reason string) error
+}
+
+// poolWatch is what the watcher last saw of one tracked pool.
+type poolWatch struct {
+	peakLiquidity float64
+	supply        float64
+	holders       map[solana.PublicKey]float64 // Token account -> balance
+	devAccount    *solana.PublicKey
+	devBalance    float64
+	alerted       map[string]bool // Each kind of alert fires once per token
+}
+
+// RugWatcher watches tracked pools for liquidity being pulled, top holders
+// or the dev dumping, and the mint authority inflating supply. Liquidity comes
+// from the price collector's samples; holders and supply are read on-chain.
+type RugWatcher struct {
+	config   types.RugWatchConfig
+	tracker  *TokenTracker
+	notifier Notifier
+	exiter   PositionExiter
+
+	mu      sync.Mutex
+	watches map[string]*poolWatch
+}
+
+func NewRugWatcher(config types.RugWatchConfig, tracker *TokenTracker, notifier Notifier, exiter PositionExiter) *RugWatcher {
+	return &RugWatcher{
+		config:   withRugDefaults(config),
+		tracker:  tracker,
+		notifier: notifier,
+		exiter:   exiter,
+		watches:  make(map[string]*poolWatch),
+	}
+}
+
+// SetConfig changes the watcher's thresholds. The check interval only
+// changes on restart.
+func (w *RugWatcher) SetConfig(config types.RugWatchConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	config = withRugDefaults(config)
+	config.CheckSeconds = w.config.CheckSeconds
+	w.config = config
+}
+
+func (w *RugWatcher) currentConfig() types.RugWatchConfig {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.config
+}
+
+func withRugDefaults(config types.RugWatchConfig) types.RugWatchConfig {
+	defaults := types.DefaultRugWatchConfig()
+	if config.LiquidityDrop <= 0 {
+		config.LiquidityDrop = defaults.LiquidityDrop
+	}
+	if config.SellShare <= 0 {
+		config.SellShare = defaults.SellShare
+	}
+	if config.SupplyIncrease <= 0 {
+		config.SupplyIncrease = defaults.SupplyIncrease
+	}
+	if config.TopHolders <= 0 {
+		config.TopHolders = defaults.TopHolders
+	}
+	if config.CheckSeconds <= 0 {
+		config.CheckSeconds = defaults.CheckSeconds
+	}
+	return config
+}
+
+func (w *RugWatcher) watch(mint string) *poolWatch {
+	watch, ok := w.watches[mint]
+	if !ok {
+		watch = &poolWatch{alerted: make(map[string]bool)}
+		w.watches[mint] = watch
+	}
+	return watch
+}
+
+// ObserveSample checks a price sample's liquidity against the pool's peak.
+func (w *RugWatcher) ObserveSample(sample types.PriceSample) {
+	w.mu.Lock()
+	watch := w.watch(sample.Mint)
+	peak := watch.peakLiquidity
+	if sample.Liquidity > peak {
+		watch.peakLiquidity = sample.Liquidity
+	}
+	maxDrop := w.config.LiquidityDrop
+	w.mu.Unlock()
+
+	if peak <= 0 || sample.Liquidity >= peak*(1-maxDrop) {
+		return
+	}
+
+	pair, ok := w.tracker.Get(sample.Mint)
+	if !ok {
+		return
+	}
+	drop := 1 - sample.Liquidity/peak
+	w.alert(pair, ALERT_LIQUIDITY_REMOVED, fmt.Sprintf("Liquidity fell %.0f%% from its peak (%.2f -> %.2f)",
+		drop*100, peak, sample.Liquidity))
+}
+
+func (w *RugWatcher) Run(ctx context.Context) {
+	interval := time.Duration(w.currentConfig().CheckSeconds) * time.Second
+	rugLog.Info("Starting rug watcher", "every", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.checkAll()
+		}
+	}
+}
+
+func (w *RugWatcher) checkAll() {
+	pairs := w.tracker.Tracked()
+
+	tracked := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		tracked[pair.Address] = true
+	}
+	w.mu.Lock()
+	for mint := range w.watches {
+		if !tracked[mint] {
+			delete(w.watches, mint)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, pair := range pairs {
+		if err := w.check(pair); err != nil {
+			pairLogger(rugLog, pair).Warn("Rug check failed", logging.Err(err))
+		}
+	}
+}
+
+// check compares supply and the watched holders' balances with the last
+// check. The first check of a pool only records the baseline. Balances are
+// read without holding the lock and written back under it.
+func (w *RugWatcher) check(pair RaydiumPair) error {
+	mint, err := solana.PublicKeyFromBase58(pair.Address)
+	if err != nil {
+		return fmt.Errorf("invalid mint address: %w", err)
+	}
+
+	supply, err := w.tokenSupply(mint)
+	if err != nil {
+		return fmt.Errorf("failed to read supply: %w", err)
+	}
+
+	w.mu.Lock()
+	watch := w.watch(pair.Address)
+	firstCheck := watch.holders == nil
+	config := w.config
+	previousSupply := watch.supply
+	previousHolders := make(map[solana.PublicKey]float64, len(watch.holders))
+	for account, balance := range watch.holders {
+		previousHolders[account] = balance
+	}
+	devAccount, previousDevBalance := watch.devAccount, watch.devBalance
+	w.mu.Unlock()
+
+	if firstCheck {
+		return w.baseline(pair, mint, supply, config.TopHolders)
+	}
+
+	if previousSupply > 0 && supply > previousSupply*(1+config.SupplyIncrease) {
+		w.alert(pair, ALERT_SUPPLY_INFLATION, fmt.Sprintf("Supply grew %.1f%% (%.0f -> %.0f); the mint authority is printing tokens",
+			(supply/previousSupply-1)*100, previousSupply, supply))
+	}
+
+	balances := make(map[solana.PublicKey]float64, len(previousHolders))
+	for account, previous := range previousHolders {
+		balance, err := TokenAccountBalance(rpcClient(), account)
+		if err != nil {
+			pairLogger(rugLog, pair).Warn("Failed to read holder balance", "holder", account, logging.Err(err))
+			continue
+		}
+		if sold := previous - balance; supply > 0 && sold/supply >= config.SellShare {
+			w.alert(pair, ALERT_HOLDER_DUMP, fmt.Sprintf("Top holder %s sold %.1f%% of supply", account, sold/supply*100))
+		}
+		balances[account] = balance
+	}
+
+	devBalance, devRead := previousDevBalance, false
+	if devAccount != nil {
+		balance, err := TokenAccountBalance(rpcClient(), *devAccount)
+		if err != nil {
+			pairLogger(rugLog, pair).Warn("Failed to read dev wallet", logging.Err(err))
+		} else {
+			if sold := previousDevBalance - balance; supply > 0 && sold/supply >= config.SellShare {
+				w.alert(pair, ALERT_DEV_SELL, fmt.Sprintf("Dev wallet sold %.1f%% of supply", sold/supply*100))
+			}
+			devBalance, devRead = balance, true
+		}
+	}
+
+	w.mu.Lock()
+	watch = w.watch(pair.Address)
+	watch.supply = supply
+	for account, balance := range balances {
+		if _, ok := watch.holders[account]; ok {
+			watch.holders[account] = balance
+		}
+	}
+	if devRead {
+		watch.devBalance = devBalance
+	}
+	w.mu.Unlock()
+
+	return nil
+}
+
+// baseline records the pool's supply, its largest holders other than the
+// pool itself, and the dev's token account, taken to be the update
+// authority's associated account.
+func (w *RugWatcher) baseline(pair RaydiumPair, mint solana.PublicKey, supply float64, topHolders int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	largest, err := rpcClient().GetTokenLargestAccounts(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		return fmt.Errorf("failed to read largest holders: %w", err)
+	}
+
+	excluded := make(map[solana.PublicKey]bool)
+	if pair.Pool.AmmId != "" {
+		if pool, err := CachedPoolAccounts(pair.Pool.AmmId); err == nil {
+			excluded[pool.BaseVault] = true
+			excluded[pool.QuoteVault] = true
+		}
+	}
+
+	holders := make(map[solana.PublicKey]float64)
+	for _, account := range largest.Value {
+		if len(holders) >= topHolders {
+			break
+		}
+		if excluded[account.Address] {
+			continue
+		}
+		balance, err := strconv.ParseFloat(account.UiAmountString, 64)
+		if err != nil {
+			continue
+		}
+		holders[account.Address] = balance
+	}
+
+	var devAccount *solana.PublicKey
+	var devBalance float64
+	if metadata, err := ResolveTokenMetadata(pair.Address); err == nil {
+		if authority, err := solana.PublicKeyFromBase58(metadata.UpdateAuthority); err == nil {
+			if account, _, err := solana.FindAssociatedTokenAddress(authority, mint); err == nil {
+				// No token account just means the dev holds nothing to sell
+				if balance, err := TokenAccountBalance(rpcClient(), account); err == nil {
+					devAccount, devBalance = &account, balance
+				}
+			}
+		}
+	}
+
+	w.mu.Lock()
+	watch := w.watch(pair.Address)
+	watch.supply = supply
+	watch.holders = holders
+	watch.devAccount = devAccount
+	watch.devBalance = devBalance
+	w.mu.Unlock()
+
+	pairLogger(rugLog, pair).Info("Watching for rugs", "supply", supply, "topHolders", len(holders),
+		"devWalletKnown", devAccount != nil)
+	return nil
+}
+
+func (w *RugWatcher) tokenSupply(mint solana.PublicKey) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := rpcClient().GetTokenSupply(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil || result.Value == nil {
+		return 0, fmt.Errorf("empty supply response")
+	}
+	return strconv.ParseFloat(result.Value.UiAmountString, 64)
+}
+
+// alert raises a critical alert and, when enabled, sells out of the token.
+func (w *RugWatcher) alert(pair RaydiumPair, kind, message string) {
+	w.mu.Lock()
+	watch := w.watch(pair.Address)
+	if watch.alerted[kind] {
+		w.mu.Unlock()
+		return
+	}
+	watch.alerted[kind] = true
+	emergencySell := w.config.EmergencySell
+	w.mu.Unlock()
+
+	logger := pairLogger(rugLog, pair)
+	logger.Error("🚨 Possible rug", "kind", kind, "detail", message)
+	alert := types.Alert{
+		Mint:     pair.Address,
+		Symbol:   pair.Symbol,
+		Kind:     kind,
+		Severity: types.SeverityCritical,
+		Message:  message,
+		Time:     time.Now(),
+	}
+	w.notifier.NotifyAlert(alert)
+	events.Publish(events.RUG_ALERT, pair.Address, pair.Symbol, alert)
+
+	if emergencySell && w.exiter != nil {
+		if err := w.exiter.EmergencyExit(pair, message); err != nil {
+			logger.Error("Emergency sell failed", logging.Err(err))
+		}
+	}
+}
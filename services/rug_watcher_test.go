@@ -0,0 +1,206 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"grind/types"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// recordingNotifier keeps the alerts and trades it is sent.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []types.Alert
+	trades []Trade
+}
+
+func (n *recordingNotifier) NotifyNewPair(RaydiumPair)            {}
+func (n *recordingNotifier) NotifySafetyReport(types.TokenReport) {}
+
+func (n *recordingNotifier) NotifyAlert(alert types.Alert) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+}
+
+func (n *recordingNotifier) NotifyTrade(trade Trade) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.trades = append(n.trades, trade)
+}
+
+type recordingExiter struct {
+	exits []string
+}
+
+func (e *recordingExiter) EmergencyExit(pair RaydiumPair, reason string) error {
+	e.exits = append(e.exits, reason)
+	return nil
+}
+
+// rpcStub answers the token supply and balance calls the rug watcher makes.
+// Every other method fails, so the dev wallet is never found.
+type rpcStub struct {
+	mu       sync.Mutex
+	supply   float64
+	balances map[string]float64 // Token account -> balance
+}
+
+func newRPCStub(t *testing.T) *rpcStub {
+	t.Helper()
+	stub := &rpcStub{balances: make(map[string]float64)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := stub.answer(request.Method, request.Params)
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		if err != nil {
+			response["error"] = map[string]interface{}{"code": -32601, "message": err.Error()}
+		} else {
+			response["result"] = map[string]interface{}{"context": map[string]int{"slot": 1}, "value": result}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	settings := DefaultSettings()
+	settings.RPCURL = server.URL
+	SetSettings(settings)
+	t.Cleanup(func() { SetSettings(DefaultSettings()) })
+	return stub
+}
+
+func tokenAmount(amount float64) map[string]interface{} {
+	ui := fmt.Sprint(amount)
+	return map[string]interface{}{"amount": ui, "decimals": 0, "uiAmount": amount, "uiAmountString": ui}
+}
+
+func (s *rpcStub) answer(method string, params []json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch method {
+	case "getTokenSupply":
+		return tokenAmount(s.supply), nil
+	case "getTokenLargestAccounts":
+		var accounts []map[string]interface{}
+		for address, balance := range s.balances {
+			account := tokenAmount(balance)
+			account["address"] = address
+			accounts = append(accounts, account)
+		}
+		return accounts, nil
+	case "getTokenAccountBalance":
+		var address string
+		if len(params) == 0 || json.Unmarshal(params[0], &address) != nil {
+			return nil, fmt.Errorf("no account given")
+		}
+		balance, ok := s.balances[address]
+		if !ok {
+			return nil, fmt.Errorf("could not find account")
+		}
+		return tokenAmount(balance), nil
+	default:
+		return nil, fmt.Errorf("method %s not stubbed", method)
+	}
+}
+
+func (s *rpcStub) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.supply = 0
+	s.balances = make(map[string]float64)
+}
+
+func (s *rpcStub) set(supply float64, holder string, balance float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.supply = supply
+	s.balances[holder] = balance
+}
+
+// checkState is the supply and the top holder's balance at one on-chain check.
+type checkState struct {
+	supply, holder float64
+}
+
+func TestRugWatcher(t *testing.T) {
+	rpc := newRPCStub(t)
+
+	for _, test := range []struct {
+		name          string
+		emergencySell bool
+		liquidity     []float64    // Price samples, in order
+		checks        []checkState // On-chain checks after the samples; the first is the baseline
+		want          []string
+	}{
+		{name: "liquidity within the drop", liquidity: []float64{100, 60, 51}},
+		{name: "liquidity pulled", liquidity: []float64{100, 40}, want: []string{ALERT_LIQUIDITY_REMOVED}},
+		{name: "drop measured from the peak", liquidity: []float64{50, 100, 45}, want: []string{ALERT_LIQUIDITY_REMOVED}},
+		{name: "one alert per kind", liquidity: []float64{100, 40, 10, 100, 5},
+			checks: []checkState{{1000, 100}, {1100, 100}, {1300, 100}}, want: []string{ALERT_LIQUIDITY_REMOVED, ALERT_SUPPLY_INFLATION}},
+		{name: "supply steady", checks: []checkState{{1000, 100}, {1005, 100}}},
+		{name: "supply inflation", checks: []checkState{{1000, 100}, {1020, 100}}, want: []string{ALERT_SUPPLY_INFLATION}},
+		{name: "top holder trims", checks: []checkState{{1000, 100}, {1000, 90}}},
+		{name: "top holder dump", checks: []checkState{{1000, 100}, {1000, 50}}, want: []string{ALERT_HOLDER_DUMP}},
+		{name: "emergency sell", emergencySell: true, liquidity: []float64{100, 40}, want: []string{ALERT_LIQUIDITY_REMOVED}},
+		{name: "emergency sell on every kind", emergencySell: true, checks: []checkState{{1000, 100}, {1020, 50}},
+			want: []string{ALERT_SUPPLY_INFLATION, ALERT_HOLDER_DUMP}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			holder, err := solana.NewRandomPrivateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pair := RaydiumPair{Address: sampleMint, Symbol: "POPCAT"}
+			tracker := NewTokenTracker("")
+			tracker.Add(pair)
+			notifier, exiter := &recordingNotifier{}, &recordingExiter{}
+			config := types.DefaultRugWatchConfig()
+			config.EmergencySell = test.emergencySell
+			watcher := NewRugWatcher(config, tracker, notifier, exiter)
+
+			for _, liquidity := range test.liquidity {
+				watcher.ObserveSample(types.PriceSample{Mint: pair.Address, Liquidity: liquidity})
+			}
+			rpc.reset()
+			for _, state := range test.checks {
+				rpc.set(state.supply, holder.PublicKey().String(), state.holder)
+				if err := watcher.check(pair); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var kinds []string
+			for _, alert := range notifier.alerts {
+				kinds = append(kinds, alert.Kind)
+				if alert.Severity != types.SeverityCritical || alert.Mint != pair.Address {
+					t.Errorf("alert = %+v", alert)
+				}
+			}
+			if fmt.Sprint(kinds) != fmt.Sprint(test.want) {
+				t.Errorf("alerts = %v, want %v", kinds, test.want)
+			}
+			wantExits := 0
+			if test.emergencySell {
+				wantExits = len(test.want)
+			}
+			if len(exiter.exits) != wantExits {
+				t.Errorf("%d emergency exits, want %d", len(exiter.exits), wantExits)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Settings are the endpoints, timings and wallet the services run with.
+type Settings struct {
+	RaydiumPairsURL string
+	RaydiumPoolURL  string // Pool info is served at RaydiumPoolURL/<AMM ID>
+	SolscanURL      string
+	GoPlusURL       string
+	RPCURL          string
+	RPCWebsocketURL string
+	WalletAddress   string
+	PrivateKey      string  // Base58 secret key of WalletAddress, for signing swaps
+	MaxSlippage     float64 // Share of a swap's quoted output it may fall short by
+	GoPlusBatchSize int     // Addresses per GoPlus token_security request
+	MaxTax          float64 // Buy or sell tax, in percent, above which a token is a honeypot
+
+	FetchInterval  time.Duration // Between TrackNewTokens cycles
+	RequestTimeout time.Duration // For single-token API requests
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		RaydiumPairsURL: "https://api.raydium.io/v2/main/pairs",
+		RaydiumPoolURL:  "https://api.raydium.io/v2/main/pool",
+		SolscanURL:      "https://public-api.solscan.io",
+		GoPlusURL:       GOPLUS_TOKEN_SECURITY_URL,
+		RPCURL:          rpc.MainNetBeta_RPC,
+		RPCWebsocketURL: rpc.MainNetBeta_WS,
+		FetchInterval:   FETCH_INTERVAL_SECONDS * time.Second,
+		RequestTimeout:  10 * time.Second,
+		MaxSlippage:     DEFAULT_MAX_SLIPPAGE,
+		GoPlusBatchSize: DEFAULT_GOPLUS_BATCH_SIZE,
+		MaxTax:          DEFAULT_MAX_TAX,
+	}
+}
+
+var (
+	settingsMu sync.RWMutex
+	settings   = DefaultSettings()
+)
+
+func SetSettings(s Settings) {
+	settingsMu.Lock()
+	settings = s
+	settingsMu.Unlock()
+
+	goPlus.SetBaseURL(s.GoPlusURL)
+	goPlus.SetBatchSize(s.GoPlusBatchSize)
+}
+
+func currentSettings() Settings {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+// MaxSlippage is the share of a swap's quoted output it may fall short by.
+func MaxSlippage() float64 {
+	return currentSettings().MaxSlippage
+}
+
+func rpcClient() *rpc.Client {
+	return rpc.New(currentSettings().RPCURL)
+}
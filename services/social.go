@@ -0,0 +1,286 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SocialLinks are the project links a token publishes in its metadata.
+type SocialLinks struct {
+	Website  string
+	Twitter  string
+	Telegram string
+	GitHub   string
+}
+
+// SocialProbe measures a single link. The HTTP implementation talks to the
+// real services; tests can swap in a stub with SetSocialProbe.
+type SocialProbe interface {
+	TwitterFollowers(handle string) (int, error)
+	TelegramMembers(channel string) (int, error)
+	PageExists(pageURL string) (bool, error)
+}
+
+// LinkResolver finds the social links published for a mint.
+type LinkResolver func(mint string) (SocialLinks, error)
+
+var (
+	socialMu     sync.RWMutex
+	socialProbe  SocialProbe  = NewHTTPSocialProbe("", "")
+	linkResolver LinkResolver = DiscoverSocialLinks
+)
+
+func SetSocialProbe(probe SocialProbe) {
+	socialMu.Lock()
+	defer socialMu.Unlock()
+	socialProbe = probe
+}
+
+func SetLinkResolver(resolver LinkResolver) {
+	socialMu.Lock()
+	defer socialMu.Unlock()
+	linkResolver = resolver
+}
+
+// DiscoverSocialLinks reads the mint's Metaplex metadata and the off-chain
+// JSON it points at.
+func DiscoverSocialLinks(mint string) (SocialLinks, error) {
+	metadata, err := ResolveTokenMetadata(mint)
+	if err != nil {
+		return SocialLinks{}, err
+	}
+	if metadata.URI == "" {
+		return SocialLinks{}, nil
+	}
+
+	offChain, err := FetchOffChainMetadata(metadata.URI)
+	if err != nil {
+		return SocialLinks{}, err
+	}
+
+	return SocialLinks{
+		Website:  firstNonEmpty(offChain.Extensions.Website, offChain.Website, offChain.ExternalURL),
+		Twitter:  firstNonEmpty(offChain.Extensions.Twitter, offChain.Twitter),
+		Telegram: firstNonEmpty(offChain.Extensions.Telegram, offChain.Telegram),
+		GitHub:   offChain.Extensions.GitHub,
+	}, nil
+}
+
+// CheckSocialPresence probes the links the token publishes. It returns
+// whatever it could measure, along with the failures of any probes that could
+// not answer; a token that publishes no links simply scores zero.
+func CheckSocialPresence(tokenAddress string) (SocialMetrics, error) {
+	socialLog.Debug("Checking social presence", "mint", tokenAddress)
+
+	socialMu.RLock()
+	probe, resolve := socialProbe, linkResolver
+	socialMu.RUnlock()
+
+	metrics := SocialMetrics{}
+	links, err := resolve(tokenAddress)
+	if err != nil {
+		return metrics, fmt.Errorf("failed to discover social links: %w", err)
+	}
+
+	var probeErrs []error
+
+	if handle := twitterHandle(links.Twitter); handle != "" {
+		followers, err := probe.TwitterFollowers(handle)
+		if err != nil {
+			probeErrs = append(probeErrs, fmt.Errorf("twitter: %w", err))
+		}
+		metrics.TwitterFollowers = followers
+	}
+
+	if channel := telegramChannel(links.Telegram); channel != "" {
+		members, err := probe.TelegramMembers(channel)
+		if err != nil {
+			probeErrs = append(probeErrs, fmt.Errorf("telegram: %w", err))
+		}
+		metrics.TelegramMembers = members
+	}
+
+	if links.Website != "" {
+		exists, err := probe.PageExists(links.Website)
+		if err != nil {
+			probeErrs = append(probeErrs, fmt.Errorf("website: %w", err))
+		}
+		metrics.WebsiteExists = exists
+
+		// Check for whitepaper
+		if exists {
+			base := strings.TrimRight(links.Website, "/")
+			for _, path := range []string{"/whitepaper.pdf", "/docs/whitepaper.pdf"} {
+				if found, err := probe.PageExists(base + path); err == nil && found {
+					metrics.HasWhitepaper = true
+					break
+				}
+			}
+		}
+	}
+
+	if links.GitHub != "" {
+		exists, err := probe.PageExists(links.GitHub)
+		if err != nil {
+			probeErrs = append(probeErrs, fmt.Errorf("github: %w", err))
+		}
+		metrics.GitHubExists = exists
+	}
+
+	socialLog.Debug("Social metrics", "mint", tokenAddress, "twitterFollowers", metrics.TwitterFollowers,
+		"telegramMembers", metrics.TelegramMembers, "website", metrics.WebsiteExists, "github", metrics.GitHubExists)
+	return metrics, errors.Join(probeErrs...)
+}
+
+// HTTPSocialProbe checks links against the Twitter, Telegram and plain HTTP endpoints.
+type HTTPSocialProbe struct {
+	client             *http.Client
+	twitterBearerToken string
+	telegramBotToken   string
+}
+
+func NewHTTPSocialProbe(twitterBearerToken, telegramBotToken string) *HTTPSocialProbe {
+	return &HTTPSocialProbe{
+		client:             &http.Client{Timeout: 10 * time.Second},
+		twitterBearerToken: twitterBearerToken,
+		telegramBotToken:   telegramBotToken,
+	}
+}
+
+func (p *HTTPSocialProbe) TwitterFollowers(handle string) (int, error) {
+	if p.twitterBearerToken == "" {
+		return 0, fmt.Errorf("no twitter API credentials configured")
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.twitter.com/2/users/by/username/%s?user.fields=public_metrics", url.PathEscape(handle)), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("Authorization", "Bearer "+p.twitterBearerToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			PublicMetrics struct {
+				FollowersCount int `json:"followers_count"`
+			} `json:"public_metrics"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Data.PublicMetrics.FollowersCount, nil
+}
+
+func (p *HTTPSocialProbe) TelegramMembers(channel string) (int, error) {
+	if p.telegramBotToken == "" {
+		return 0, fmt.Errorf("no telegram bot token configured")
+	}
+
+	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/getChatMemberCount?chat_id=@%s",
+		p.telegramBotToken, url.QueryEscape(channel))
+	resp, err := p.client.Get(endpoint)
+	if err != nil {
+		// The URL holds the bot token, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("failed to call telegram getChatMemberCount: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok          bool   `json:"ok"`
+		Result      int    `json:"result"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.Ok {
+		return 0, fmt.Errorf("API error: %s", result.Description)
+	}
+	return result.Result, nil
+}
+
+func (p *HTTPSocialProbe) PageExists(pageURL string) (bool, error) {
+	resp, err := p.client.Head(pageURL)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	// Some sites reject HEAD outright; only a 404/410 says the page is missing
+	switch {
+	case resp.StatusCode < 400:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+// twitterHandle accepts "@name", "name" or a twitter.com/x.com profile URL.
+func twitterHandle(link string) string {
+	return profileName(link, "twitter.com", "x.com")
+}
+
+// telegramChannel accepts "@name", "name" or a t.me URL.
+func telegramChannel(link string) string {
+	return profileName(link, "t.me", "telegram.me")
+}
+
+// profileName is the bare name, or the first path segment of a URL on one of
+// hosts, so links to a post or message still give the profile.
+func profileName(link string, hosts ...string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	if !strings.Contains(link, "/") {
+		return strings.TrimPrefix(link, "@")
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		parsed, err = url.Parse("https://" + link)
+		if err != nil {
+			return ""
+		}
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	for _, allowed := range hosts {
+		if host == allowed {
+			segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+			return strings.TrimPrefix(segments[0], "@")
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
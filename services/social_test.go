@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const sampleMint = "7GCihgDB8fe6KNjn2MYtkzZcRjQy3t9GHdC8uHYmW2hr"
+
+// stubProbe answers from fixed tables and records what it was asked.
+type stubProbe struct {
+	followers map[string]int
+	members   map[string]int
+	pages     map[string]bool
+	failing   map[string]bool
+	asked     []string
+}
+
+func (p *stubProbe) TwitterFollowers(handle string) (int, error) {
+	p.asked = append(p.asked, "twitter:"+handle)
+	if p.failing[handle] {
+		return 0, errors.New("rate limited")
+	}
+	return p.followers[handle], nil
+}
+
+func (p *stubProbe) TelegramMembers(channel string) (int, error) {
+	p.asked = append(p.asked, "telegram:"+channel)
+	if p.failing[channel] {
+		return 0, errors.New("chat not found")
+	}
+	return p.members[channel], nil
+}
+
+func (p *stubProbe) PageExists(pageURL string) (bool, error) {
+	p.asked = append(p.asked, pageURL)
+	if p.failing[pageURL] {
+		return false, errors.New("unexpected status code: 503")
+	}
+	return p.pages[pageURL], nil
+}
+
+func withSocialStubs(t *testing.T, probe SocialProbe, links SocialLinks, linksErr error) {
+	t.Helper()
+	SetSocialProbe(probe)
+	SetLinkResolver(func(string) (SocialLinks, error) { return links, linksErr })
+	t.Cleanup(func() {
+		SetSocialProbe(NewHTTPSocialProbe("", ""))
+		SetLinkResolver(DiscoverSocialLinks)
+	})
+}
+
+func TestProfileNames(t *testing.T) {
+	for _, test := range []struct {
+		link, twitter, telegram string
+	}{
+		{"", "", ""},
+		{"@popcat", "popcat", "popcat"},
+		{"popcat", "popcat", "popcat"},
+		{"https://twitter.com/popcat", "popcat", ""},
+		{"https://x.com/popcat/status/1799", "popcat", ""},
+		{"www.twitter.com/@popcat/", "popcat", ""},
+		{"https://t.me/popcatsol", "", "popcatsol"},
+		{"t.me/popcatsol/42", "", "popcatsol"},
+		{"https://telegram.me/popcatsol", "", "popcatsol"},
+		{"https://popcat.xyz/twitter", "", ""},
+	} {
+		if got := twitterHandle(test.link); got != test.twitter {
+			t.Errorf("twitterHandle(%q) = %q, want %q", test.link, got, test.twitter)
+		}
+		if got := telegramChannel(test.link); got != test.telegram {
+			t.Errorf("telegramChannel(%q) = %q, want %q", test.link, got, test.telegram)
+		}
+	}
+}
+
+func TestCheckSocialPresence(t *testing.T) {
+	probe := &stubProbe{
+		followers: map[string]int{"popcat": 12000},
+		members:   map[string]int{"popcatsol": 800},
+		pages:     map[string]bool{"https://popcat.xyz/": true, "https://popcat.xyz/docs/whitepaper.pdf": true},
+	}
+	withSocialStubs(t, probe, SocialLinks{
+		Website:  "https://popcat.xyz/",
+		Twitter:  "https://x.com/popcat",
+		Telegram: "t.me/popcatsol",
+	}, nil)
+
+	metrics, err := CheckSocialPresence(sampleMint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SocialMetrics{TwitterFollowers: 12000, TelegramMembers: 800, WebsiteExists: true, HasWhitepaper: true}
+	if metrics != want {
+		t.Errorf("got %+v, want %+v", metrics, want)
+	}
+}
+
+func TestCheckSocialPresenceKeepsPartialResults(t *testing.T) {
+	probe := &stubProbe{
+		members: map[string]int{"popcatsol": 800},
+		failing: map[string]bool{"popcat": true, "https://github.com/popcat": true},
+	}
+	withSocialStubs(t, probe, SocialLinks{Twitter: "@popcat", Telegram: "popcatsol", GitHub: "https://github.com/popcat"}, nil)
+
+	metrics, err := CheckSocialPresence(sampleMint)
+	if err == nil {
+		t.Fatalf("failed probes were not reported")
+	}
+	if metrics.TelegramMembers != 800 {
+		t.Errorf("dropped the telegram count alongside the failures: %+v", metrics)
+	}
+}
+
+func TestCheckSocialPresenceWithoutLinks(t *testing.T) {
+	probe := &stubProbe{}
+	withSocialStubs(t, probe, SocialLinks{}, nil)
+
+	metrics, err := CheckSocialPresence(sampleMint)
+	if err != nil || metrics != (SocialMetrics{}) || len(probe.asked) > 0 {
+		t.Errorf("got %+v, %v after asking %v; want zero metrics without probing", metrics, err, probe.asked)
+	}
+
+	withSocialStubs(t, probe, SocialLinks{}, errors.New("rpc down"))
+	if _, err := CheckSocialPresence(sampleMint); err == nil {
+		t.Errorf("a failed link lookup was not reported")
+	}
+}
+
+// failingTransport fails every request without touching the network.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTelegramMembersHidesBotToken(t *testing.T) {
+	const token = "123456:secret-bot-token"
+	probe := NewHTTPSocialProbe("", token)
+	probe.client = &http.Client{Transport: failingTransport{}}
+
+	_, err := probe.TelegramMembers("popcatsol")
+	if err == nil {
+		t.Fatal("a failed request was not reported")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("the bot token leaked into %q", err)
+	}
+}
+
+func TestDiscoverSocialLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/extensions.json":
+			fmt.Fprint(w, `{"external_url": "https://old.popcat.xyz", "twitter": "@old",
+				"extensions": {"website": "https://popcat.xyz", "twitter": "https://x.com/popcat", "github": "https://github.com/popcat"}}`)
+		case "/top-level.json":
+			fmt.Fprint(w, `{"external_url": "https://popcat.xyz", "twitter": "@popcat", "telegram": "t.me/popcatsol"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		uri     string
+		want    SocialLinks
+		wantErr bool
+	}{
+		{"", SocialLinks{}, false},
+		{server.URL + "/extensions.json", SocialLinks{Website: "https://popcat.xyz", Twitter: "https://x.com/popcat", GitHub: "https://github.com/popcat"}, false},
+		{server.URL + "/top-level.json", SocialLinks{Website: "https://popcat.xyz", Twitter: "@popcat", Telegram: "t.me/popcatsol"}, false},
+		{server.URL + "/missing.json", SocialLinks{}, true},
+	} {
+		mint := "mint-" + test.uri
+		tokenCache.Set(mint, CacheMetadata, TokenMetadata{Mint: mint, URI: test.uri})
+
+		links, err := DiscoverSocialLinks(mint)
+		if (err != nil) != test.wantErr || links != test.want {
+			t.Errorf("%q: got %+v, %v; want %+v", test.uri, links, err, test.want)
+		}
+	}
+}
@@ -2,32 +2,225 @@ package services
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
 )
 
+type trackedToken struct {
+	Pair    RaydiumPair `json:"pair"`
+	AddedAt time.Time   `json:"addedAt"`
+}
+
+// trackerState is the layout of the tracker file.
+type trackerState struct {
+	Tracked []trackedToken       `json:"tracked"`
+	Seen    map[string]time.Time `json:"seen"`
+}
+
+// TokenTracker holds the tokens being followed and every token already
+// evaluated, and keeps both in a JSON file so a restart picks up where it
+// left off.
 type TokenTracker struct {
-	filepath string
+	mu        sync.RWMutex
+	filepath  string
+	tokens    map[string]trackedToken
+	seen      map[string]time.Time
+	maxTokens int
+	maxAge    time.Duration
 }
 
 func NewTokenTracker(filename string) *TokenTracker {
 	return &TokenTracker{
-		filepath: filename,
+		filepath:  filename,
+		tokens:    make(map[string]trackedToken),
+		seen:      make(map[string]time.Time),
+		maxTokens: MAX_TOKENS_TO_TRACK,
+		maxAge:    MAX_MARKET_AGE,
 	}
 }
 
+// SetLimits changes how many tokens are tracked at once and how old they may get.
+func (t *TokenTracker) SetLimits(maxTokens int, maxAge time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxTokens = maxTokens
+	t.maxAge = maxAge
+}
+
+// Load restores the tracker from its file, dropping expired entries. A missing
+// file is an empty tracker.
+func (t *TokenTracker) Load() error {
+	data, err := os.ReadFile(t.filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tracker file: %w", err)
+	}
+
+	var state trackerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode tracker file: %w", err)
+	}
+
+	t.mu.Lock()
+	for _, token := range state.Tracked {
+		t.tokens[token.Pair.Address] = token
+	}
+	for mint, at := range state.Seen {
+		t.seen[mint] = at
+	}
+	t.mu.Unlock()
+
+	t.Expire(time.Now())
+
+	t.mu.RLock()
+	trackerLog.Info("Restored tracker", "tracked", len(t.tokens), "seen", len(t.seen), "path", t.filepath)
+	t.mu.RUnlock()
+	return nil
+}
+
+// Save writes the tracker file atomically.
+func (t *TokenTracker) Save() error {
+	t.mu.RLock()
+	state := trackerState{
+		Tracked: make([]trackedToken, 0, len(t.tokens)),
+		Seen:    make(map[string]time.Time, len(t.seen)),
+	}
+	for _, token := range t.tokens {
+		state.Tracked = append(state.Tracked, token)
+	}
+	for mint, at := range t.seen {
+		state.Seen[mint] = at
+	}
+	t.mu.RUnlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tracker state: %w", err)
+	}
+
+	if err := writeFileAtomic(t.filepath, data); err != nil {
+		return fmt.Errorf("failed to save tracker file: %w", err)
+	}
+	return nil
+}
+
+// Add starts tracking pair. Once the tracking limit is reached the oldest
+// token makes way.
 func (t *TokenTracker) Add(pair RaydiumPair) {
 	// Skip invalid tokens
 	if pair.Address == "" || pair.Address == "11111111111111111111111111111111" {
-		log.Printf("Skipping invalid token: %s", pair.Name)
+		trackerLog.Debug("Skipping invalid token", "name", pair.Name)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if existing, ok := t.tokens[pair.Address]; ok {
+		existing.Pair = pair
+		t.tokens[pair.Address] = existing
 		return
 	}
-	log.Printf("Added token: %s (%s)", pair.Name, pair.Address)
-	// TODO: Implement persistence to file if needed
+
+	if len(t.tokens) >= t.maxTokens {
+		var oldest trackedToken
+		for _, token := range t.tokens {
+			if oldest.Pair.Address == "" || token.AddedAt.Before(oldest.AddedAt) {
+				oldest = token
+			}
+		}
+		delete(t.tokens, oldest.Pair.Address)
+		pairLogger(trackerLog, oldest.Pair).Info("Tracking limit reached, dropped oldest token", "limit", t.maxTokens)
+	}
+
+	t.tokens[pair.Address] = trackedToken{Pair: pair, AddedAt: time.Now()}
+	pairLogger(trackerLog, pair).Info("✅ Tracking token")
+}
+
+// Tracked returns the pairs currently being tracked.
+func (t *TokenTracker) Tracked() []RaydiumPair {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	pairs := make([]RaydiumPair, 0, len(t.tokens))
+	for _, token := range t.tokens {
+		pairs = append(pairs, token.Pair)
+	}
+	return pairs
 }
+
+func (t *TokenTracker) Get(mint string) (RaydiumPair, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	token, ok := t.tokens[mint]
+	return token.Pair, ok
+}
+
+// MarkSeen records that mint has been evaluated so it is not evaluated again.
+func (t *TokenTracker) MarkSeen(mint string, at time.Time) {
+	t.mu.Lock()
+	t.seen[mint] = at
+	t.mu.Unlock()
+}
+
+func (t *TokenTracker) Seen(mint string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	_, ok := t.seen[mint]
+	return ok
+}
+
+// Evaluated lists every mint evaluated within the maximum age, whether it
+// passed or not.
+func (t *TokenTracker) Evaluated() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	mints := make([]string, 0, len(t.seen))
+	for mint := range t.seen {
+		mints = append(mints, mint)
+	}
+	return mints
+}
+
+// Expire drops tracked tokens whose market is older than the maximum age, and
+// seen tokens first seen longer ago than that.
+func (t *TokenTracker) Expire(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for mint, token := range t.tokens {
+		if now.Sub(listedAt(token)) > t.maxAge {
+			delete(t.tokens, mint)
+			pairLogger(trackerLog, token.Pair).Info("Stopped tracking token", "maxAge", t.maxAge)
+		}
+	}
+	for mint, at := range t.seen {
+		if now.Sub(at) > t.maxAge {
+			delete(t.seen, mint)
+		}
+	}
+}
+
+// listedAt is when the token's market opened, or when tracking began if the
+// pairs feed gave no timestamp.
+func listedAt(token trackedToken) time.Time {
+	if listed, err := time.Parse(time.RFC3339, token.Pair.Timestamp); err == nil {
+		return listed
+	}
+	return token.AddedAt
+}
+
 func LogRawPairSample(pairs []interface{}, sampleSize int) {
-	log.Printf("Sampling first %d raw pairs:", sampleSize)
 	for i := 0; i < min(sampleSize, len(pairs)); i++ {
-		rawJSON, _ := json.MarshalIndent(pairs[i], "", "  ")
-		log.Printf("Raw pair %d: %s", i, string(rawJSON))
+		rawJSON, _ := json.Marshal(pairs[i])
+		raydiumLog.Debug("Raw pair", "index", i, "json", string(rawJSON))
 	}
 }
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func trackedMints(tracker *TokenTracker) []string {
+	var mints []string
+	for _, pair := range tracker.Tracked() {
+		mints = append(mints, pair.Address)
+	}
+	sort.Strings(mints)
+	return mints
+}
+
+func TestTokenTrackerSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tracker.json")
+	listed := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	mints := randomMints(t, 3)
+
+	tracker := NewTokenTracker(path)
+	tracker.Add(RaydiumPair{Address: mints[0], Symbol: "ONE", Timestamp: listed.Format(time.RFC3339)})
+	tracker.Add(RaydiumPair{Address: mints[1], Symbol: "TWO"})
+	tracker.MarkSeen(mints[2], listed)
+	if err := tracker.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is written through a temporary file renamed into place
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("directory holds %v, %v; want only the tracker file", entries, err)
+	}
+
+	restored := NewTokenTracker(path)
+	if err := restored.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := trackedMints(restored), trackedMints(tracker); !reflect.DeepEqual(got, want) {
+		t.Errorf("tracked = %v, want %v", got, want)
+	}
+	if pair, ok := restored.Get(mints[0]); !ok || pair.Symbol != "ONE" || pair.Timestamp != listed.Format(time.RFC3339) {
+		t.Errorf("restored pair = %+v", pair)
+	}
+	if !restored.Seen(mints[2]) || restored.Seen(mints[0]) {
+		t.Errorf("seen = %v, want only %s", restored.Evaluated(), mints[2])
+	}
+
+	if err := NewTokenTracker(filepath.Join(dir, "missing.json")).Load(); err != nil {
+		t.Errorf("a missing file is not an empty tracker: %v", err)
+	}
+}
+
+func TestTokenTrackerExpire(t *testing.T) {
+	now := time.Now()
+	mints := randomMints(t, 5)
+	tracker := NewTokenTracker("")
+	tracker.SetLimits(10, time.Hour)
+
+	tracker.Add(RaydiumPair{Address: mints[0], Timestamp: now.Add(-2 * time.Hour).Format(time.RFC3339)})
+	tracker.Add(RaydiumPair{Address: mints[1], Timestamp: now.Add(-30 * time.Minute).Format(time.RFC3339)})
+	// Without a listing time the token ages from when it was added
+	tracker.Add(RaydiumPair{Address: mints[2]})
+	tracker.MarkSeen(mints[3], now.Add(-90*time.Minute))
+	tracker.MarkSeen(mints[4], now.Add(-10*time.Minute))
+
+	tracker.Expire(now)
+	want := []string{mints[1], mints[2]}
+	sort.Strings(want)
+	if got := trackedMints(tracker); !reflect.DeepEqual(got, want) {
+		t.Errorf("tracked = %v, want %v", got, want)
+	}
+	if tracker.Seen(mints[3]) || !tracker.Seen(mints[4]) {
+		t.Errorf("seen = %v, want only %s", tracker.Evaluated(), mints[4])
+	}
+
+	tracker.Expire(now.Add(2 * time.Hour))
+	if len(tracker.Tracked()) != 0 || len(tracker.Evaluated()) != 0 {
+		t.Errorf("tracked %v and seen %v past the maximum age", trackedMints(tracker), tracker.Evaluated())
+	}
+}
+
+func TestTokenTrackerEvictsOldest(t *testing.T) {
+	mints := randomMints(t, 4)
+	tracker := NewTokenTracker("")
+	tracker.SetLimits(3, time.Hour)
+
+	for _, mint := range mints[:3] {
+		tracker.Add(RaydiumPair{Address: mint})
+		time.Sleep(time.Millisecond)
+	}
+	// Updating a tracked pair neither evicts nor counts as a new entry
+	tracker.Add(RaydiumPair{Address: mints[0], Symbol: "UPDATED"})
+	if len(tracker.Tracked()) != 3 {
+		t.Fatalf("tracked = %v after an update", trackedMints(tracker))
+	}
+
+	tracker.Add(RaydiumPair{Address: mints[3]})
+	want := append([]string{}, mints[1:]...)
+	sort.Strings(want)
+	if got := trackedMints(tracker); !reflect.DeepEqual(got, want) {
+		t.Errorf("tracked = %v, want the oldest, %s, dropped", got, mints[0])
+	}
+}
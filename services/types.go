@@ -0,0 +1,41 @@
+package services
+
+import "grind/types"
+
+// Shared data types live in the types package so that db, notifications and
+// analytics can use them without importing services.
+type (
+	RaydiumPair   = types.RaydiumPair
+	RaydiumPool   = types.RaydiumPool
+	PoolAccounts  = types.PoolAccounts
+	SocialMetrics = types.SocialMetrics
+	SafetyReport  = types.SafetyReport
+	SafetyCheck   = types.SafetyCheck
+	Position      = types.Position
+	Trade         = types.Trade
+
+	TokenMetrics       = types.TokenMetrics
+	TokenSafetyMetrics = types.TokenSafetyMetrics
+)
+
+const (
+	MAX_MARKET_CAP_USD     = types.MAX_MARKET_CAP_USD
+	MIN_HOLDER_COUNT       = types.MIN_HOLDER_COUNT
+	MIN_MARKET_AGE         = types.MIN_MARKET_AGE
+	MAX_MARKET_AGE         = types.MAX_MARKET_AGE
+	FETCH_INTERVAL_SECONDS = types.FETCH_INTERVAL_SECONDS
+	MAX_TOKENS_TO_TRACK    = types.MAX_TOKENS_TO_TRACK
+)
+
+type Database interface {
+	StoreSafetyReport(report SafetyReport) error
+	StoreScores(mint string, scores []types.ScoreBreakdown) error
+	StoreObservation(observation types.TokenObservation) error
+}
+
+type Notifier interface {
+	NotifyNewPair(pair RaydiumPair)
+	NotifySafetyReport(token types.TokenReport)
+	NotifyAlert(alert types.Alert)
+	NotifyTrade(trade Trade)
+}
@@ -2,64 +2,207 @@ package services
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"log"
+	"math"
+	"time"
+
+	"grind/events"
+	"grind/logging"
+	"grind/metrics"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 )
 
-func GetWallet() solana.PublicKey {
-	// Replace this with your Phantom wallet's private key if you want to use the full wallet
-	// Or just use the public address if you only need to receive funds
-	phantomAddress := "79hjkpSwnJ4g7PJ7YYQfJRGEwHwWWUB7ziyve15fC4YC" // Replace this with your address
-	pubKey, err := solana.PublicKeyFromBase58(phantomAddress)
+const (
+	CONFIRMATION_TIMEOUT = 90 * time.Second
+	DEFAULT_MAX_SLIPPAGE = 0.05
+	ANY_SLIPPAGE         = 1.0 // Accepts any amount out
+	// SWAP_FEE is the share of the amount in the pool keeps
+	SWAP_FEE = 0.0025
+)
+
+var SWAP_PROGRAM_ID = solana.MustPublicKeyFromBase58("SwaPpA9LAaLfeLi3a68M4DjnLqgtticKg6CnyNwgAC8")
+
+// GetWallet is the configured trading wallet.
+func GetWallet() (solana.PublicKey, error) {
+	pubKey, err := solana.PublicKeyFromBase58(currentSettings().WalletAddress)
 	if err != nil {
-		log.Fatalf("Failed to parse wallet address: %v", err)
+		return solana.PublicKey{}, fmt.Errorf("invalid wallet address: %w", err)
 	}
-	return pubKey
+	return pubKey, nil
 }
 
-func AttemptBuy(wallet solana.PublicKey, targetToken solana.PublicKey, amount float64) error {
-	// Connect to Solana mainnet
-	client := rpc.New(rpc.MainNetBeta_RPC)
+// AttemptBuy swaps amount of the other side of the pair's pool (SOL for the
+// usual SOL pairs) into the pair's token. Confirmation is awaited in the
+// background and published as an event; use AwaitConfirmation to wait for it.
+func AttemptBuy(wallet solana.PublicKey, pair RaydiumPair, amount float64) (sig solana.Signature, err error) {
+	outcome := metrics.BUY_FAILED
+	defer func() {
+		if err == nil {
+			outcome = metrics.BUY_SENT
+		}
+		metrics.Buys.WithLabelValues(outcome).Inc()
+	}()
 
-	// Add these definitions before creating swap instruction
-	ammId := solana.MustPublicKeyFromBase58("YOUR_AMM_ID_HERE")
-	userSourceTokenAccount := wallet // This should be your SOL account
-	poolSourceTokenAccount := solana.MustPublicKeyFromBase58("POOL_SOURCE_TOKEN_ACCOUNT")
-	poolDestinationTokenAccount := solana.MustPublicKeyFromBase58("POOL_DESTINATION_TOKEN_ACCOUNT")
-	userDestinationTokenAccount := solana.MustPublicKeyFromBase58("YOUR_TARGET_TOKEN_ACCOUNT")
-	lpMint := solana.MustPublicKeyFromBase58("LP_MINT_ADDRESS")
-	feeAccount := solana.MustPublicKeyFromBase58("FEE_ACCOUNT_ADDRESS")
+	client := rpcClient()
 
-	// Rest of the implementation remains the same
 	balance := CheckBalance(client, wallet)
 	if balance < amount {
-		return fmt.Errorf("insufficient balance: %.2f SOL", balance)
+		outcome = metrics.BUY_INSUFFICIENT_BALANCE
+		return sig, fmt.Errorf("insufficient balance: %.2f SOL", balance)
+	}
+
+	sig, err = swapThroughPool(client, wallet, pair, false, amount, currentSettings().MaxSlippage)
+	if err != nil {
+		return sig, err
+	}
+
+	trade := TradeEvent{Signature: sig.String(), Amount: amount}
+	events.Publish(events.BUY_SENT, pair.Address, pair.Symbol, trade)
+	go func() {
+		if err := AwaitConfirmation(sig); err != nil {
+			pairLogger(tradeLog, pair).Warn("Buy not confirmed", "signature", trade.Signature, logging.Err(err))
+			return
+		}
+		events.Publish(events.BUY_CONFIRMED, pair.Address, pair.Symbol, trade)
+	}()
+	return sig, nil
+}
+
+// AttemptSell swaps amount of the pair's token back into the other side of
+// its pool, failing if it would return more than slippage less than the
+// quote. ANY_SLIPPAGE sells at any price.
+func AttemptSell(wallet solana.PublicKey, pair RaydiumPair, amount, slippage float64) (solana.Signature, error) {
+	return swapThroughPool(rpcClient(), wallet, pair, true, amount, slippage)
+}
+
+// swapThroughPool swaps amount through the pair's pool, out of the pair's
+// token when sell is set and into it otherwise. The swap fails on-chain if it
+// would return less than the pool's current quote less slippage; a slippage
+// of ANY_SLIPPAGE or more accepts any amount.
+func swapThroughPool(client *rpc.Client, wallet solana.PublicKey, pair RaydiumPair, sell bool, amount, slippage float64) (solana.Signature, error) {
+	ammId, err := solana.PublicKeyFromBase58(pair.Pool.AmmId)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("invalid AMM ID: %w", err)
+	}
+	lpMint, err := solana.PublicKeyFromBase58(pair.Pool.LpMint)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("invalid LP mint: %w", err)
+	}
+	pool, err := CachedPoolAccounts(pair.Pool.AmmId)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to fetch pool accounts: %w", err)
+	}
+
+	// Work out which side of the pool the token is on
+	tokenMint, otherMint := pair.Pool.BaseMint, pair.Pool.QuoteMint
+	tokenVault, otherVault := pool.BaseVault, pool.QuoteVault
+	tokenDecimals, otherDecimals := pair.Pool.BaseDecimals, pair.Pool.QuoteDecimals
+	if pair.Pool.QuoteMint == pair.Address {
+		tokenMint, otherMint = pair.Pool.QuoteMint, pair.Pool.BaseMint
+		tokenVault, otherVault = pool.QuoteVault, pool.BaseVault
+		tokenDecimals, otherDecimals = pair.Pool.QuoteDecimals, pair.Pool.BaseDecimals
+	}
+
+	fromMint, toMint := otherMint, tokenMint
+	poolSource, poolDestination := otherVault, tokenVault
+	decimals, outDecimals := otherDecimals, tokenDecimals
+	if sell {
+		fromMint, toMint = tokenMint, otherMint
+		poolSource, poolDestination = tokenVault, otherVault
+		decimals, outDecimals = tokenDecimals, otherDecimals
+	}
+
+	var minAmountOut uint64
+	if slippage < ANY_SLIPPAGE {
+		quote, err := quoteSwap(client, poolSource, poolDestination, amount)
+		if err != nil {
+			return solana.Signature{}, fmt.Errorf("failed to quote swap: %w", err)
+		}
+		minAmountOut = uint64(quote * (1 - slippage) * math.Pow10(outDecimals))
+	}
+
+	userSourceTokenAccount, err := associatedTokenAccount(wallet, fromMint)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	userDestinationTokenAccount, err := associatedTokenAccount(wallet, toMint)
+	if err != nil {
+		return solana.Signature{}, err
 	}
 
-	// Create swap instruction
-	programID := solana.MustPublicKeyFromBase58("SwaPpA9LAaLfeLi3a68M4DjnLqgtticKg6CnyNwgAC8")
 	instruction := CreateSwapInstruction(
-		programID,
+		SWAP_PROGRAM_ID,
 		ammId,
 		userSourceTokenAccount,
-		poolSourceTokenAccount,
-		poolDestinationTokenAccount,
+		poolSource,
+		poolDestination,
 		userDestinationTokenAccount,
 		lpMint,
-		feeAccount,
+		pool.FeeAccount,
 		wallet,
-		uint64(amount*1e9),
-		uint64(0),
+		uint64(amount*math.Pow10(decimals)),
+		minAmountOut,
 	)
 
+	return sendInstruction(client, wallet, instruction)
+}
+
+// quoteSwap is what amount into the pool's source vault returns from its
+// destination vault at the current reserves, after the pool's fee.
+func quoteSwap(client *rpc.Client, source, destination solana.PublicKey, amount float64) (float64, error) {
+	reserveIn, err := TokenAccountBalance(client, source)
+	if err != nil {
+		return 0, err
+	}
+	reserveOut, err := TokenAccountBalance(client, destination)
+	if err != nil {
+		return 0, err
+	}
+	if reserveIn <= 0 || reserveOut <= 0 {
+		return 0, errors.New("pool has no reserves")
+	}
+	in := amount * (1 - SWAP_FEE)
+	return reserveOut * in / (reserveIn + in), nil
+}
+
+// TokenBalance is how much of mint the wallet holds; no token account means none.
+func TokenBalance(wallet solana.PublicKey, mint string) (float64, error) {
+	account, err := associatedTokenAccount(wallet, mint)
+	if err != nil {
+		return 0, err
+	}
+	balance, err := TokenAccountBalance(rpcClient(), account)
+	if err != nil {
+		if _, infoErr := rpcClient().GetAccountInfo(context.Background(), account); errors.Is(infoErr, rpc.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return balance, nil
+}
+
+func associatedTokenAccount(wallet solana.PublicKey, mint string) (solana.PublicKey, error) {
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("invalid mint address %q: %w", mint, err)
+	}
+	account, _, err := solana.FindAssociatedTokenAddress(wallet, mintKey)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive token account: %w", err)
+	}
+	return account, nil
+}
+
+func sendInstruction(client *rpc.Client, wallet solana.PublicKey, instruction solana.Instruction) (solana.Signature, error) {
 	// Get recent blockhash
 	recentBlockhash, err := client.GetRecentBlockhash(context.Background(), rpc.CommitmentFinalized)
 	if err != nil {
-		return fmt.Errorf("failed to get recent blockhash: %w", err)
+		return solana.Signature{}, fmt.Errorf("failed to get recent blockhash: %w", err)
 	}
 
 	// Create and use the instruction in a transaction
@@ -69,18 +212,84 @@ func AttemptBuy(wallet solana.PublicKey, targetToken solana.PublicKey, amount fl
 		solana.TransactionPayer(wallet),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+		return solana.Signature{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	signer, err := signingKey(wallet)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(wallet) {
+			return &signer
+		}
+		return nil
+	}); err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
 	// Send the transaction
 	sig, err := client.SendTransaction(context.Background(), tx)
 	if err != nil {
-		return fmt.Errorf("failed to send transaction: %w", err)
+		return solana.Signature{}, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	tradeLog.Info("Transaction sent", "signature", sig.String())
+
+	return sig, nil
+}
+
+// signingKey is the configured secret key, which must belong to wallet.
+func signingKey(wallet solana.PublicKey) (solana.PrivateKey, error) {
+	secret := currentSettings().PrivateKey
+	if secret == "" {
+		return nil, errors.New("no signing key configured (set trading.privateKey or GRIND_WALLET_PRIVATE_KEY)")
 	}
+	key, err := solana.PrivateKeyFromBase58(secret)
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid signing key")
+	}
+	if !key.PublicKey().Equals(wallet) {
+		return nil, fmt.Errorf("signing key is not the key of wallet %s", wallet)
+	}
+	return key, nil
+}
+
+// TradeEvent is the payload of buy events.
+type TradeEvent struct {
+	Signature string  `json:"signature"`
+	Amount    float64 `json:"amount"`
+}
+
+// AwaitConfirmation polls until the transaction is confirmed, fails, or
+// CONFIRMATION_TIMEOUT passes.
+func AwaitConfirmation(sig solana.Signature) error {
+	client := rpcClient()
+	ctx, cancel := context.WithTimeout(context.Background(), CONFIRMATION_TIMEOUT)
+	defer cancel()
 
-	log.Printf("Transaction sent: %s", sig.String())
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
 
-	return nil
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not confirmed within %s", CONFIRMATION_TIMEOUT)
+		case <-ticker.C:
+		}
+
+		result, err := client.GetSignatureStatuses(ctx, false, sig)
+		if err != nil || result == nil || len(result.Value) == 0 || result.Value[0] == nil {
+			continue // Not seen yet
+		}
+		status := result.Value[0]
+		if status.Err != nil {
+			return fmt.Errorf("transaction failed: %v", status.Err)
+		}
+		if status.ConfirmationStatus == rpc.ConfirmationStatusConfirmed || status.ConfirmationStatus == rpc.ConfirmationStatusFinalized {
+			return nil
+		}
+	}
 }
 
 func CreateSwapInstruction(
@@ -96,10 +305,10 @@ func CreateSwapInstruction(
 	amountIn uint64,
 	minAmountOut uint64,
 ) solana.Instruction {
-	data := make([]byte, 10)
+	data := make([]byte, 17)
 	data[0] = 9 // Swap instruction code
 	binary.LittleEndian.PutUint64(data[1:], amountIn)
-	data[9] = uint8(minAmountOut)
+	binary.LittleEndian.PutUint64(data[9:], minAmountOut)
 
 	accounts := solana.AccountMetaSlice{
 		{PublicKey: ammId, IsSigner: false, IsWritable: true},
@@ -122,7 +331,7 @@ func CheckBalance(client *rpc.Client, wallet solana.PublicKey) float64 {
 		rpc.CommitmentFinalized,
 	)
 	if err != nil {
-		log.Printf("Failed to get balance: %v", err)
+		tradeLog.Warn("Failed to get balance", "wallet", wallet, logging.Err(err))
 		return 0
 	}
 	return float64(balance.Value) / 1e9 // Convert lamports to SOL
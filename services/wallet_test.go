@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestCreateSwapInstruction(t *testing.T) {
+	account := solana.MustPublicKeyFromBase58(sampleMint)
+	owner := solana.MustPublicKeyFromBase58(sampleUpdateAuthority)
+	minAmountOut := uint64(1_234_567_890_123)
+
+	instruction := CreateSwapInstruction(SWAP_PROGRAM_ID, account, account, account, account, account, account, account,
+		owner, 5_000_000, minAmountOut)
+	data, err := instruction.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) != 17 || data[0] != 9 {
+		t.Fatalf("data = %v, want 17 bytes of swap instruction 9", data)
+	}
+	if got := binary.LittleEndian.Uint64(data[1:]); got != 5_000_000 {
+		t.Errorf("amount in = %d", got)
+	}
+	if got := binary.LittleEndian.Uint64(data[9:]); got != minAmountOut {
+		t.Errorf("minimum out = %d, want %d", got, minAmountOut)
+	}
+
+	var signers []solana.PublicKey
+	for _, meta := range instruction.Accounts() {
+		if meta.IsSigner {
+			signers = append(signers, meta.PublicKey)
+		}
+	}
+	if len(signers) != 1 || !signers[0].Equals(owner) {
+		t.Errorf("signers = %v, want the owner alone", signers)
+	}
+}
+
+func TestSigningKey(t *testing.T) {
+	key, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetSettings(DefaultSettings()) })
+
+	for _, test := range []struct {
+		name   string
+		secret string
+		wallet solana.PublicKey
+		ok     bool
+	}{
+		{"matching", key.String(), key.PublicKey(), true},
+		{"none configured", "", key.PublicKey(), false},
+		{"another wallet", key.String(), solana.MustPublicKeyFromBase58(sampleMint), false},
+		{"too short", key.PublicKey().String(), key.PublicKey(), false},
+	} {
+		settings := DefaultSettings()
+		settings.PrivateKey = test.secret
+		SetSettings(settings)
+
+		signer, err := signingKey(test.wallet)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: got %v", test.name, err)
+		}
+		if test.ok && !signer.PublicKey().Equals(test.wallet) {
+			t.Errorf("%s: signer is %s", test.name, signer.PublicKey())
+		}
+	}
+}
+
+func TestGetWallet(t *testing.T) {
+	t.Cleanup(func() { SetSettings(DefaultSettings()) })
+
+	settings := DefaultSettings()
+	settings.WalletAddress = sampleMint
+	SetSettings(settings)
+	if wallet, err := GetWallet(); err != nil || wallet.String() != sampleMint {
+		t.Errorf("got %s, %v", wallet, err)
+	}
+
+	settings.WalletAddress = "not-a-wallet"
+	SetSettings(settings)
+	if _, err := GetWallet(); err == nil {
+		t.Errorf("an invalid wallet address was accepted")
+	}
+}
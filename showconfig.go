@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"grind/config"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configPaths is the base config plus the overlay for the active profile.
+func configPaths(base, profile string) ([]string, error) {
+	if base == "" {
+		base = CONFIG_PATH
+	}
+	return config.ProfilePaths(base, profile)
+}
+
+// runShowConfig prints the effective config, after overlays and environment
+// overrides, with secrets masked.
+func runShowConfig(args []string) error {
+	flags := flag.NewFlagSet("config", flag.ExitOnError)
+	var source configSource
+	source.register(flags)
+	outputFormat := formatFlag(flags, FORMAT_JSON, FORMAT_YAML)
+	flags.Parse(args)
+
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+
+	// Not source.load: an invalid config is still worth looking at
+	paths, err := source.files()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg, err := config.LoadConfig(paths...)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Warning, config is invalid:\n%v", err)
+	}
+
+	// Round-trip through JSON so both formats use the same keys
+	data, err := json.Marshal(cfg.Redacted())
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if format == FORMAT_YAML {
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		return encoder.Encode(tree)
+	}
+	return printResult(os.Stdout, format, tree, nil)
+}
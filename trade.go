@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"grind/config"
+	"grind/services"
+	"grind/types"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// tradeResult is what `grind buy` and `grind sell` report.
+type tradeResult struct {
+	Side      string             `json:"side"`
+	Mint      string             `json:"mint"`
+	Symbol    string             `json:"symbol"`
+	Amount    float64            `json:"amount"` // SOL spent on a buy, tokens sold on a sell
+	Signature string             `json:"signature"`
+	Tokens    float64            `json:"tokens"`             // Tokens received on a buy
+	Position  *services.Position `json:"position,omitempty"` // As it stands after the trade
+	Closed    bool               `json:"closed,omitempty"`
+}
+
+// tradeFlags are the flags and arguments buy and sell share.
+type tradeFlags struct {
+	configSource
+	format string
+	yes    bool
+	mint   string
+	amount string
+}
+
+func parseTradeFlags(name, amountName string, args []string) (*tradeFlags, error) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	var trade tradeFlags
+	trade.register(flags)
+	outputFormat := formatFlag(flags, FORMAT_TABLE, FORMAT_JSON)
+	flags.BoolVar(&trade.yes, "yes", false, "skip the confirmation prompt")
+	positional, err := argsAfterFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+	if trade.format, err = outputFormat(); err != nil {
+		return nil, err
+	}
+	if len(positional) != 2 {
+		return nil, fmt.Errorf("usage: grind %s [flags] <mint> <%s>", name, amountName)
+	}
+	trade.mint, trade.amount = positional[0], positional[1]
+	if _, err := solana.PublicKeyFromBase58(trade.mint); err != nil {
+		return nil, fmt.Errorf("invalid mint address: %w", err)
+	}
+	return &trade, nil
+}
+
+// prepareTrade loads the config and positions and finds the mint's pool.
+func prepareTrade(trade *tradeFlags) (*config.Config, *services.PositionBook, services.RaydiumPair, error) {
+	cfg, _, err := trade.load()
+	if err != nil {
+		return nil, nil, services.RaydiumPair{}, err
+	}
+	if err := configureServices(cfg); err != nil {
+		return nil, nil, services.RaydiumPair{}, err
+	}
+
+	positions := services.NewPositionBook(cfg.Storage.PositionsPath)
+	if err := positions.Load(); err != nil {
+		return nil, nil, services.RaydiumPair{}, fmt.Errorf("failed to load positions: %w", err)
+	}
+
+	pair, found, err := services.FindPair(trade.mint)
+	if err != nil {
+		return nil, nil, services.RaydiumPair{}, fmt.Errorf("failed to fetch pairs: %w", err)
+	}
+	if !found {
+		return nil, nil, services.RaydiumPair{}, fmt.Errorf("no Raydium pool found for %s", trade.mint)
+	}
+	return cfg, positions, pair, nil
+}
+
+// runBuy swaps SOL into a token, waits for confirmation and opens a position
+// for the tokens received.
+func runBuy(args []string) error {
+	trade, err := parseTradeFlags("buy", "sol", args)
+	if err != nil {
+		return err
+	}
+	amount, err := strconv.ParseFloat(trade.amount, 64)
+	if err != nil || amount <= 0 {
+		return errors.New("amount must be a positive number of SOL")
+	}
+
+	cfg, positions, pair, err := prepareTrade(trade)
+	if err != nil {
+		return err
+	}
+
+	if !trade.yes && !confirm(fmt.Sprintf("Buy %s (%s) with %g SOL from wallet %s at about $%.8f, accepting up to %g%% slippage?",
+		pair.Symbol, pair.Address, amount, cfg.Trading.WalletAddress, pair.Price, cfg.Trading.MaxSlippage*100)) {
+		return errors.New("cancelled")
+	}
+
+	notifier, err := newNotifiers(cfg)
+	if err != nil {
+		return err
+	}
+	defer notifier.Close()
+
+	result, err := executeBuy(pair, amount, "manual", positions, notifier, printSent)
+	if err != nil {
+		return err
+	}
+	return printTrade(trade.format, result)
+}
+
+// executeBuy swaps amount SOL into pair's token, waits for confirmation and
+// opens a position for the tokens received. sent is called before the wait.
+func executeBuy(pair services.RaydiumPair, amount float64, reason string, positions *services.PositionBook,
+	notifier *notifiers, sent func(solana.Signature)) (tradeResult, error) {
+	wallet, err := services.GetWallet()
+	if err != nil {
+		return tradeResult{}, err
+	}
+	before, err := services.TokenBalance(wallet, pair.Address)
+	if err != nil {
+		return tradeResult{}, fmt.Errorf("failed to read token balance: %w", err)
+	}
+	notice := types.Trade{Side: types.TradeBuy, Mint: pair.Address, Symbol: pair.Symbol, Amount: amount, Reason: reason}
+
+	sig, err := services.AttemptBuy(wallet, pair, amount)
+	if err != nil {
+		notifyTrade(notifier, notice, sig, err)
+		return tradeResult{}, fmt.Errorf("buy failed: %w", err)
+	}
+	sent(sig)
+	err = services.AwaitConfirmation(sig)
+	notifyTrade(notifier, notice, sig, err)
+	if err != nil {
+		return tradeResult{}, fmt.Errorf("buy %s: %w", sig, err)
+	}
+
+	after, err := services.TokenBalance(wallet, pair.Address)
+	if err != nil {
+		return tradeResult{}, fmt.Errorf("buy %s confirmed but the token balance could not be read: %w", sig, err)
+	}
+	result := tradeResult{Side: "buy", Mint: pair.Address, Symbol: pair.Symbol, Amount: amount,
+		Signature: sig.String(), Tokens: after - before}
+	if result.Tokens > 0 {
+		positions.Open(services.Position{Mint: pair.Address, Symbol: pair.Symbol, Amount: result.Tokens,
+			EntryPrice: pair.Price, OpenedAt: time.Now()})
+		if position, ok := positions.Get(pair.Address); ok {
+			result.Position = &position
+		}
+	}
+	return result, nil
+}
+
+// runSell swaps tokens back out of a position, waits for confirmation and
+// reduces or closes the position.
+func runSell(args []string) error {
+	trade, err := parseTradeFlags("sell", "amount|all", args)
+	if err != nil {
+		return err
+	}
+
+	cfg, positions, pair, err := prepareTrade(trade)
+	if err != nil {
+		return err
+	}
+
+	position, held := positions.Get(pair.Address)
+	var amount float64
+	if trade.amount == "all" {
+		if !held {
+			return fmt.Errorf("no open position in %s", pair.Symbol)
+		}
+		amount = position.Amount
+	} else if amount, err = strconv.ParseFloat(trade.amount, 64); err != nil || amount <= 0 {
+		return errors.New("amount must be a positive number of tokens or all")
+	}
+
+	prompt := fmt.Sprintf("Sell %g %s (%s) at about $%.8f, accepting up to %g%% slippage?",
+		amount, pair.Symbol, pair.Address, pair.Price, cfg.Trading.MaxSlippage*100)
+	if held {
+		prompt = fmt.Sprintf("%s Position holds %g.", prompt, position.Amount)
+	}
+	if !trade.yes && !confirm(prompt) {
+		return errors.New("cancelled")
+	}
+
+	notifier, err := newNotifiers(cfg)
+	if err != nil {
+		return err
+	}
+	defer notifier.Close()
+
+	result, err := executeSell(pair, amount, "manual", positions, notifier, printSent)
+	if err != nil {
+		return err
+	}
+	return printTrade(trade.format, result)
+}
+
+// executeSell swaps amount of pair's token back out, waits for confirmation
+// and reduces or closes any position in it. sent is called before the wait.
+func executeSell(pair services.RaydiumPair, amount float64, reason string, positions *services.PositionBook,
+	notifier *notifiers, sent func(solana.Signature)) (tradeResult, error) {
+	notice := types.Trade{Side: types.TradeSell, Mint: pair.Address, Symbol: pair.Symbol, Amount: amount, Reason: reason}
+
+	wallet, err := services.GetWallet()
+	if err != nil {
+		return tradeResult{}, err
+	}
+	sig, err := services.AttemptSell(wallet, pair, amount, services.MaxSlippage())
+	if err != nil {
+		notifyTrade(notifier, notice, sig, err)
+		return tradeResult{}, fmt.Errorf("sell failed: %w", err)
+	}
+	sent(sig)
+	err = services.AwaitConfirmation(sig)
+	notifyTrade(notifier, notice, sig, err)
+	if err != nil {
+		return tradeResult{}, fmt.Errorf("sell %s: %w", sig, err)
+	}
+
+	result := tradeResult{Side: "sell", Mint: pair.Address, Symbol: pair.Symbol, Amount: amount, Signature: sig.String()}
+	if position, held := positions.Get(pair.Address); held {
+		remaining, _ := positions.Reduce(pair.Address, amount)
+		if amount < position.Amount {
+			result.Position = &remaining
+		} else {
+			result.Closed = true
+		}
+	}
+	return result, nil
+}
+
+// trade is the result as notifications show it.
+func (r tradeResult) trade(reason string) types.Trade {
+	return types.Trade{Side: types.TradeSide(r.Side), Mint: r.Mint, Symbol: r.Symbol, Amount: r.Amount,
+		Signature: r.Signature, Reason: reason, Time: time.Now()}
+}
+
+func printSent(sig solana.Signature) {
+	fmt.Fprintf(os.Stderr, "Sent %s, waiting for confirmation...\n", sig)
+}
+
+// notifyTrade sends a trade made from the command line to the notification
+// channels, as the scanner does for its own.
+func notifyTrade(notifier *notifiers, trade types.Trade, sig solana.Signature, err error) {
+	if !sig.IsZero() {
+		trade.Signature = sig.String()
+	}
+	if err != nil {
+		trade.Error = err.Error()
+	}
+	trade.Time = time.Now()
+	notifier.NotifyTrade(trade)
+}
+
+func printTrade(format string, result tradeResult) error {
+	return printResult(os.Stdout, format, result, func(w io.Writer) {
+		fmt.Fprintf(w, "Side\t%s\n", result.Side)
+		fmt.Fprintf(w, "Token\t%s (%s)\n", result.Symbol, result.Mint)
+		if result.Side == "buy" {
+			fmt.Fprintf(w, "Spent\t%g SOL\n", result.Amount)
+			fmt.Fprintf(w, "Received\t%g\n", result.Tokens)
+		} else {
+			fmt.Fprintf(w, "Sold\t%g\n", result.Amount)
+		}
+		fmt.Fprintf(w, "Signature\t%s\n", result.Signature)
+		if result.Position != nil {
+			fmt.Fprintf(w, "Position\t%g at $%.8f\n", result.Position.Amount, result.Position.EntryPrice)
+		} else if result.Closed {
+			fmt.Fprintf(w, "Position\tclosed\n")
+		}
+	})
+}
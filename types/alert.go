@@ -0,0 +1,59 @@
+package types
+
+import "time"
+
+type AlertSeverity string
+
+const (
+	SeverityInfo     AlertSeverity = "info"
+	SeverityWarning  AlertSeverity = "warning"
+	SeverityCritical AlertSeverity = "critical"
+)
+
+// Alert is something about a tracked token that needs attention now.
+type Alert struct {
+	Mint     string        `json:"mint"`
+	Symbol   string        `json:"symbol"`
+	Kind     string        `json:"kind"`
+	Severity AlertSeverity `json:"severity"`
+	Message  string        `json:"message"`
+	Time     time.Time     `json:"time"`
+}
+
+var severityRanks = map[AlertSeverity]int{
+	SeverityInfo:     0,
+	SeverityWarning:  1,
+	SeverityCritical: 2,
+}
+
+func (s AlertSeverity) Valid() bool {
+	_, ok := severityRanks[s]
+	return ok
+}
+
+// AtLeast reports whether s is as severe as min or more.
+func (s AlertSeverity) AtLeast(min AlertSeverity) bool {
+	return severityRanks[s] >= severityRanks[min]
+}
+
+// RugWatchConfig sets how far a tracked pool may move before it is treated
+// as a rug. Shares and drops are fractions (0.5 = 50%).
+type RugWatchConfig struct {
+	LiquidityDrop  float64 `json:"liquidityDrop"`  // Fall from peak liquidity that counts as removal
+	SellShare      float64 `json:"sellShare"`      // Share of supply a top holder or the dev may sell between checks
+	SupplyIncrease float64 `json:"supplyIncrease"` // Growth in supply that counts as inflation
+	TopHolders     int     `json:"topHolders"`     // How many of the largest token accounts are watched
+	CheckSeconds   int     `json:"checkSeconds"`   // How often holders and supply are read on-chain
+	// EmergencySell sells any open position when a rug is detected; it needs trading.privateKey
+	EmergencySell bool `json:"emergencySell"`
+}
+
+func DefaultRugWatchConfig() RugWatchConfig {
+	return RugWatchConfig{
+		LiquidityDrop:  0.5,
+		SellShare:      0.02,
+		SupplyIncrease: 0.01,
+		TopHolders:     5,
+		CheckSeconds:   30,
+	}
+}
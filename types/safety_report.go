@@ -0,0 +1,154 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+type CheckStatus string
+
+const (
+	CheckPass    CheckStatus = "pass"
+	CheckFail    CheckStatus = "fail"
+	CheckWarn    CheckStatus = "warn"
+	CheckUnknown CheckStatus = "unknown"
+)
+
+// CheckPolicy decides what an unknown result means when a data source fails.
+type CheckPolicy string
+
+const (
+	FailClosed CheckPolicy = "fail_closed" // Unknown blocks the token like a failure
+	FailOpen   CheckPolicy = "fail_open"   // Unknown is reported but does not block
+)
+
+// Safety checks that rely on a data source, named as in safety.failurePolicies.
+const (
+	CHECK_LIQUIDITY_LOCK      = "liquidity_lock"
+	CHECK_HONEYPOT            = "honeypot"
+	CHECK_TOP_HOLDER_SHARE    = "top_holder_share"
+	CHECK_HOLDER_COUNT        = "holder_count"
+	CHECK_SOCIAL_PRESENCE     = "social_presence"
+	CHECK_METADATA_MUTABILITY = "metadata_mutability"
+)
+
+// SOURCED_CHECKS lists every check a failure policy can be set for.
+var SOURCED_CHECKS = []string{
+	CHECK_LIQUIDITY_LOCK, CHECK_HONEYPOT, CHECK_TOP_HOLDER_SHARE,
+	CHECK_HOLDER_COUNT, CHECK_SOCIAL_PRESENCE, CHECK_METADATA_MUTABILITY,
+}
+
+// IsSourcedCheck reports whether name is one of SOURCED_CHECKS.
+func IsSourcedCheck(name string) bool {
+	for _, check := range SOURCED_CHECKS {
+		if name == check {
+			return true
+		}
+	}
+	return false
+}
+
+// SafetyCheck is the outcome of a single check, with enough context to explain it.
+type SafetyCheck struct {
+	Name      string      `json:"name"`
+	Status    CheckStatus `json:"status"`
+	Value     string      `json:"value,omitempty"`
+	Threshold string      `json:"threshold,omitempty"`
+	Source    string      `json:"source"`
+	Message   string      `json:"message,omitempty"`
+	Policy    CheckPolicy `json:"policy,omitempty"`
+}
+
+// Blocking reports whether the check should reject the token.
+func (c SafetyCheck) Blocking() bool {
+	return c.Status == CheckFail || (c.Status == CheckUnknown && c.Policy != FailOpen)
+}
+
+// SafetyReport lists every check run against a token, not just the first failure.
+type SafetyReport struct {
+	Mint        string        `json:"mint"`
+	Symbol      string        `json:"symbol,omitempty"`
+	GeneratedAt time.Time     `json:"generatedAt"`
+	Checks      []SafetyCheck `json:"checks"`
+}
+
+func NewSafetyReport(mint, symbol string) *SafetyReport {
+	return &SafetyReport{
+		Mint:        mint,
+		Symbol:      symbol,
+		GeneratedAt: time.Now(),
+		Checks:      make([]SafetyCheck, 0),
+	}
+}
+
+func (r *SafetyReport) Add(check SafetyCheck) {
+	r.Checks = append(r.Checks, check)
+}
+
+// Passed reports whether no check failed and no fail-closed check is unknown.
+func (r *SafetyReport) Passed() bool {
+	return len(r.BlockingChecks()) == 0
+}
+
+func (r *SafetyReport) BlockingChecks() []SafetyCheck {
+	checks := make([]SafetyCheck, 0)
+	for _, check := range r.Checks {
+		if check.Blocking() {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+func (r *SafetyReport) Failures() []SafetyCheck {
+	return r.withStatus(CheckFail)
+}
+
+func (r *SafetyReport) Warnings() []SafetyCheck {
+	return r.withStatus(CheckWarn)
+}
+
+func (r *SafetyReport) Unknowns() []SafetyCheck {
+	return r.withStatus(CheckUnknown)
+}
+
+// Summary joins the messages of every blocking check, for log lines.
+func (r *SafetyReport) Summary() string {
+	blocking := r.BlockingChecks()
+	if len(blocking) == 0 {
+		return "all checks passed"
+	}
+
+	reasons := make([]string, 0, len(blocking))
+	for _, check := range blocking {
+		reasons = append(reasons, check.Message)
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func (r *SafetyReport) withStatus(status CheckStatus) []SafetyCheck {
+	checks := make([]SafetyCheck, 0)
+	for _, check := range r.Checks {
+		if check.Status == status {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// TokenReport is a token that went through the filters, with what a
+// notification shows about it.
+type TokenReport struct {
+	Pair   RaydiumPair        `json:"pair"`
+	Report SafetyReport       `json:"report"`
+	Safety TokenSafetyMetrics `json:"safety"`
+	Scores []ScoreBreakdown   `json:"scores,omitempty"` // Primary first
+}
+
+// Score is the primary model's score, if the token was scored.
+func (t TokenReport) Score() (ScoreBreakdown, bool) {
+	if len(t.Scores) == 0 {
+		return ScoreBreakdown{}, false
+	}
+	return t.Scores[0], true
+}
@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+type TradeSide string
+
+const (
+	TradeBuy  TradeSide = "buy"
+	TradeSell TradeSide = "sell"
+)
+
+// Trade is a buy or sell the bot sent, for notifications.
+type Trade struct {
+	Side      TradeSide `json:"side"`
+	Mint      string    `json:"mint"`
+	Symbol    string    `json:"symbol"`
+	Amount    float64   `json:"amount"` // SOL spent on a buy, tokens sold on a sell
+	Signature string    `json:"signature,omitempty"`
+	Reason    string    `json:"reason,omitempty"` // Why the trade was made, e.g. an emergency exit
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Severity is critical for a failed trade, as a failed sell can leave funds stuck.
+func (t Trade) Severity() AlertSeverity {
+	if t.Error != "" {
+		return SeverityCritical
+	}
+	return SeverityInfo
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -33,6 +34,7 @@ type RaydiumPair struct {
 	MarketCap    float64     `json:"marketCap"`
 	TokenAmount  float64     `json:"tokenAmount"`
 	TokenAddress string      `json:"tokenAddress"`
+	URI          string      `json:"uri,omitempty"` // Metaplex metadata URI, filled on-chain
 }
 
 type RaydiumPool struct {
@@ -50,15 +52,124 @@ type RaydiumPool struct {
 	TokenAmountPc   float64 `json:"tokenAmountPc"`
 }
 
+type TokenMetrics struct {
+	Liquidity float64
+	Volume24h float64
+	MarketCap float64
+	// Add other needed fields
+}
+
+type TokenSafetyMetrics struct {
+	LiquidityLocked   bool
+	LiquidityLockTime time.Duration
+	IsHoneypot        bool
+	TopHolderShare    float64
+	HolderCount       int
+	SocialMetrics     SocialMetrics
+	MetadataMutable   bool
+	UpdateAuthority   string
+	// Unavailable maps a check name to why its data source could not answer
+	Unavailable map[string]string
+}
+
+func (s TokenSafetyMetrics) Known(check string) bool {
+	_, unavailable := s.Unavailable[check]
+	return !unavailable
+}
+
+func (s *TokenSafetyMetrics) MarkUnavailable(err error, checks ...string) {
+	if s.Unavailable == nil {
+		s.Unavailable = make(map[string]string)
+	}
+	for _, check := range checks {
+		s.Unavailable[check] = err.Error()
+	}
+}
+
+// TokenObservation is everything the scanner knew about a candidate when it
+// evaluated it, kept so the decision can be replayed under other settings.
+type TokenObservation struct {
+	Pair       RaydiumPair        `json:"pair"`
+	Metrics    TokenMetrics       `json:"metrics"`
+	Safety     TokenSafetyMetrics `json:"safety"`
+	ObservedAt time.Time          `json:"observedAt"`
+}
+
+type PricePoint struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// CacheEntry is a persisted result of one safety or metrics lookup for a mint.
+type CacheEntry struct {
+	Mint      string
+	Check     string
+	Value     json.RawMessage
+	ExpiresAt time.Time
+}
+
 const (
-	PHANTOM_WALLET_ADDRESS = "79hjkpSwnJ4g7PJ7YYQfJRGEwHwWWUB7ziyve15fC4YC"
-	MIN_LIQUIDITY_USD      = 500.0
 	MAX_MARKET_CAP_USD     = 1000000.0
 	MIN_HOLDER_COUNT       = 100
-	MIN_PRICE              = 0.0
-	MAX_PRICE              = 1.0
 	MIN_MARKET_AGE         = 1 * time.Hour
 	MAX_MARKET_AGE         = 24 * time.Hour
 	FETCH_INTERVAL_SECONDS = 5
 	MAX_TOKENS_TO_TRACK    = 10 // Maximum number of new tokens to track at once
+
+	DEFAULT_PRICE_SAMPLE_SECONDS = 60
+
+	// Where price samples come from: the Raydium pairs feed or the pool vaults
+	PRICE_SOURCE_PAIRS    = "pairs"
+	PRICE_SOURCE_RESERVES = "reserves"
 )
+
+// ScoreComponent is one term of a score: its input value, the weight or
+// factor applied, and what it contributed.
+type ScoreComponent struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// ScoreBreakdown explains a model's score: Total is the sum of the
+// components' contributions times the product of the multipliers.
+type ScoreBreakdown struct {
+	Model       string           `json:"model"`
+	Total       float64          `json:"total"`
+	Components  []ScoreComponent `json:"components"`
+	Multipliers []ScoreComponent `json:"multipliers,omitempty"`
+}
+
+// PriceSample is one reading of a tracked token's market.
+type PriceSample struct {
+	Mint      string    `json:"mint"`
+	Time      time.Time `json:"time"`
+	Price     float64   `json:"price"`
+	Liquidity float64   `json:"liquidity"`
+	Volume24h float64   `json:"volume24h"`
+}
+
+// Candle is an OHLCV bar. Volume is the traded volume attributed to the bar,
+// derived from increases in the rolling 24h volume between samples.
+type Candle struct {
+	Mint      string    `json:"mint"`
+	Interval  string    `json:"interval"`
+	OpenTime  time.Time `json:"openTime"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+	Liquidity float64   `json:"liquidity"` // As of the last sample
+	Samples   int       `json:"samples"`
+}
+
+// Position is a holding bought by the bot.
+type Position struct {
+	Mint       string    `json:"mint"`
+	Symbol     string    `json:"symbol"`
+	Amount     float64   `json:"amount"`     // Tokens held
+	EntryPrice float64   `json:"entryPrice"` // USD per token
+	OpenedAt   time.Time `json:"openedAt"`
+}